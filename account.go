@@ -0,0 +1,177 @@
+package sdkservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	auth "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vesting "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	gogoproto "github.com/gogo/protobuf/proto"
+	"google.golang.org/grpc/codes"
+)
+
+// AccountDetails describes an account, including the vesting schedule of
+// vesting accounts.
+type AccountDetails struct {
+	Address       string          `json:"address"`
+	Type          string          `json:"type"`
+	AccountNumber uint64          `json:"account_number"`
+	Sequence      uint64          `json:"sequence"`
+	PublicKey     *PublicKey      `json:"public_key,omitempty"`
+	Module        *ModuleAccount  `json:"module,omitempty"`
+	Vesting       *VestingDetails `json:"vesting,omitempty"`
+}
+
+// PublicKey is a public key and the type URL of its algorithm.
+type PublicKey struct {
+	Type string `json:"type"`
+	Key  []byte `json:"key"`
+}
+
+// ModuleAccount holds the name and permissions of module accounts.
+type ModuleAccount struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// VestingDetails holds the vesting schedule of an account. StartTime is
+// unset for delayed and permanently locked accounts, and EndTime for the
+// latter.
+type VestingDetails struct {
+	OriginalVesting  []Coin          `json:"original_vesting"`
+	DelegatedVesting []Coin          `json:"delegated_vesting"`
+	DelegatedFree    []Coin          `json:"delegated_free"`
+	StartTime        *time.Time      `json:"start_time,omitempty"`
+	EndTime          *time.Time      `json:"end_time,omitempty"`
+	Periods          []VestingPeriod `json:"periods,omitempty"`
+}
+
+// VestingPeriod is a period of a periodic vesting account, at the end of
+// which Amount is unlocked.
+type VestingPeriod struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Length int64     `json:"length"`
+	Amount []Coin    `json:"amount"`
+}
+
+// account returns the account of addr, or nil if the nodes answer it
+// doesn't exist, which is the case of addresses which never received funds.
+func (q *chainQuerier) account(ctx context.Context, addr string) (auth.AccountI, error) {
+	res, err := auth.NewQueryClient(q.conn).Account(ctx, &auth.QueryAccountRequest{
+		Address: addr,
+	})
+	if err != nil {
+		if grpcCode(err) == codes.NotFound {
+			return nil, nil
+		}
+
+		return nil, queryError("auth", err)
+	}
+
+	if res == nil || res.Account == nil {
+		return nil, newError(KindNotFound, fmt.Errorf("account has no numbers associated"))
+	}
+
+	var accountI auth.AccountI
+	if err := q.cdc.UnpackAny(res.Account, &accountI); err != nil {
+		return nil, fmt.Errorf("cannot unpack account %s of type %s, %w", addr, res.Account.TypeUrl, err)
+	}
+
+	return accountI, nil
+}
+
+// AccountDetails returns the type, numbers, public key and vesting schedule
+// of address.
+func (q *chainQuerier) AccountDetails(ctx context.Context, address AccountAddress) (AccountDetails, error) {
+	addr, err := q.bech32Address(address)
+	if err != nil {
+		return AccountDetails{}, err
+	}
+
+	account, err := q.account(ctx, addr)
+	if err != nil {
+		return AccountDetails{}, err
+	}
+
+	if account == nil {
+		return AccountDetails{}, newError(KindNotFound, fmt.Errorf("account %s not found", addr))
+	}
+
+	ret := AccountDetails{
+		Address:       addr,
+		Type:          "/" + gogoproto.MessageName(account),
+		AccountNumber: account.GetAccountNumber(),
+		Sequence:      account.GetSequence(),
+		PublicKey:     publicKey(account.GetPubKey()),
+	}
+
+	if ma, ok := account.(auth.ModuleAccountI); ok {
+		ret.Module = &ModuleAccount{
+			Name:        ma.GetName(),
+			Permissions: append([]string{}, ma.GetPermissions()...),
+		}
+	}
+
+	if va, ok := account.(vesting.VestingAccount); ok {
+		ret.Vesting = vestingDetails(va)
+	}
+
+	return ret, nil
+}
+
+func publicKey(pk cryptotypes.PubKey) *PublicKey {
+	if pk == nil {
+		return nil
+	}
+
+	return &PublicKey{
+		Type: "/" + gogoproto.MessageName(pk),
+		Key:  pk.Bytes(),
+	}
+}
+
+func vestingDetails(va vesting.VestingAccount) *VestingDetails {
+	ret := &VestingDetails{
+		OriginalVesting:  coins(va.GetOriginalVesting()),
+		DelegatedVesting: coins(va.GetDelegatedVesting()),
+		DelegatedFree:    coins(va.GetDelegatedFree()),
+	}
+
+	switch a := va.(type) {
+	case *vestingtypes.ContinuousVestingAccount:
+		ret.StartTime = unixTime(a.StartTime)
+		ret.EndTime = unixTime(a.EndTime)
+	case *vestingtypes.DelayedVestingAccount:
+		ret.EndTime = unixTime(a.EndTime)
+	case *vestingtypes.PeriodicVestingAccount:
+		ret.StartTime = unixTime(a.StartTime)
+		ret.EndTime = unixTime(a.EndTime)
+
+		start := a.StartTime
+		for _, p := range a.VestingPeriods {
+			ret.Periods = append(ret.Periods, VestingPeriod{
+				Start:  time.Unix(start, 0).UTC(),
+				End:    time.Unix(start+p.Length, 0).UTC(),
+				Length: p.Length,
+				Amount: coins(p.Amount),
+			})
+
+			start += p.Length
+		}
+	case *vestingtypes.PermanentLockedAccount:
+	default:
+		ret.StartTime = unixTime(va.GetStartTime())
+		ret.EndTime = unixTime(va.GetEndTime())
+	}
+
+	return ret
+}
+
+func unixTime(sec int64) *time.Time {
+	t := time.Unix(sec, 0).UTC()
+	return &t
+}
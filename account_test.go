@@ -0,0 +1,164 @@
+package sdkservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	auth "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	gogoproto "github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAccountDetailsVesting(t *testing.T) {
+	const now = testVestingBlockTime
+	at := func(sec int64) *time.Time { return unixTime(sec) }
+
+	delegated := vestingtypes.NewContinuousVestingAccount(baseVestingAccount(), atoms(100), now-50, now+50)
+	delegated.DelegatedVesting = atoms(30)
+	delegated.DelegatedFree = atoms(10)
+
+	tests := []struct {
+		name    string
+		account gogoproto.Message
+		want    *VestingDetails
+	}{
+		{
+			name:    "base account",
+			account: baseVestingAccount(),
+		},
+		{
+			name:    "continuous",
+			account: vestingtypes.NewContinuousVestingAccount(baseVestingAccount(), atoms(100), now-50, now+50),
+			want: &VestingDetails{
+				OriginalVesting:  []Coin{{Denom: "uatom", Amount: "100"}},
+				DelegatedVesting: []Coin{},
+				DelegatedFree:    []Coin{},
+				StartTime:        at(now - 50),
+				EndTime:          at(now + 50),
+			},
+		},
+		{
+			name:    "delayed",
+			account: vestingtypes.NewDelayedVestingAccount(baseVestingAccount(), atoms(100), now+10),
+			want: &VestingDetails{
+				OriginalVesting:  []Coin{{Denom: "uatom", Amount: "100"}},
+				DelegatedVesting: []Coin{},
+				DelegatedFree:    []Coin{},
+				EndTime:          at(now + 10),
+			},
+		},
+		{
+			name: "periodic",
+			account: vestingtypes.NewPeriodicVestingAccount(baseVestingAccount(), atoms(100), now-15, vestingtypes.Periods{
+				{Length: 10, Amount: atoms(40)},
+				{Length: 10, Amount: atoms(60)},
+			}),
+			want: &VestingDetails{
+				OriginalVesting:  []Coin{{Denom: "uatom", Amount: "100"}},
+				DelegatedVesting: []Coin{},
+				DelegatedFree:    []Coin{},
+				StartTime:        at(now - 15),
+				EndTime:          at(now + 5),
+				Periods: []VestingPeriod{
+					{Start: *at(now - 15), End: *at(now - 5), Length: 10, Amount: []Coin{{Denom: "uatom", Amount: "40"}}},
+					{Start: *at(now - 5), End: *at(now + 5), Length: 10, Amount: []Coin{{Denom: "uatom", Amount: "60"}}},
+				},
+			},
+		},
+		{
+			name:    "permanently locked",
+			account: vestingtypes.NewPermanentLockedAccount(baseVestingAccount(), atoms(100)),
+			want: &VestingDetails{
+				OriginalVesting:  []Coin{{Denom: "uatom", Amount: "100"}},
+				DelegatedVesting: []Coin{},
+				DelegatedFree:    []Coin{},
+			},
+		},
+		{
+			name:    "delegated",
+			account: delegated,
+			want: &VestingDetails{
+				OriginalVesting:  []Coin{{Denom: "uatom", Amount: "100"}},
+				DelegatedVesting: []Coin{{Denom: "uatom", Amount: "30"}},
+				DelegatedFree:    []Coin{{Denom: "uatom", Amount: "10"}},
+				StartTime:        at(now - 50),
+				EndTime:          at(now + 50),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		q := testQuerier("cosmos-hub", SDKv44, vestingConn(t, atoms(100), tt.account))
+
+		ret, err := q.AccountDetails(context.Background(), AccountAddress{Bech32: testAccAddress.String()})
+		require.NoError(t, err, tt.name)
+		require.Equal(t, "/"+gogoproto.MessageName(tt.account), ret.Type, tt.name)
+		require.Equal(t, uint64(4), ret.AccountNumber, tt.name)
+		require.Equal(t, uint64(2), ret.Sequence, tt.name)
+		require.Equal(t, tt.want, ret.Vesting, tt.name)
+	}
+}
+
+func TestAccountDetails(t *testing.T) {
+	pk := secp256k1.GenPrivKey().PubKey()
+
+	base := baseVestingAccount()
+	require.NoError(t, base.SetPubKey(pk))
+
+	q := testQuerier("cosmos-hub", SDKv44, vestingConn(t, atoms(100), base))
+
+	ret, err := q.AccountDetails(context.Background(), AccountAddress{Bech32: testAccAddress.String()})
+	require.NoError(t, err)
+	require.Equal(t, &PublicKey{Type: "/cosmos.crypto.secp256k1.PubKey", Key: pk.Bytes()}, ret.PublicKey)
+	require.Nil(t, ret.Module)
+	require.Nil(t, ret.Vesting)
+
+	module := auth.NewEmptyModuleAccount("distribution", auth.Minter, auth.Burner)
+	q = testQuerier("cosmos-hub", SDKv44, vestingConn(t, atoms(100), module))
+
+	ret, err = q.AccountDetails(context.Background(), AccountAddress{Bech32: module.Address})
+	require.NoError(t, err)
+	require.Equal(t, &ModuleAccount{Name: "distribution", Permissions: []string{auth.Minter, auth.Burner}}, ret.Module)
+	require.Nil(t, ret.PublicKey)
+
+	// Accounts which never received funds don't exist.
+	q = testQuerier("cosmos-hub", SDKv44, newFakeConn(map[string]interface{}{
+		accountMethod: status.Errorf(codes.NotFound, "account %s not found", testAccAddress),
+	}))
+
+	_, err = q.AccountDetails(context.Background(), AccountAddress{Bech32: testAccAddress.String()})
+	require.Equal(t, KindNotFound, ClassifyError(err).Kind)
+}
+
+func TestAccountNumbers(t *testing.T) {
+	addr := AccountAddress{Bech32: testAccAddress.String()}
+
+	q := testQuerier("cosmos-hub", SDKv44, vestingConn(t, atoms(100), baseVestingAccount()))
+
+	ret, err := q.AccountNumbers(context.Background(), addr)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), ret.AccountNumber)
+	require.Equal(t, int64(2), ret.SequenceNumber)
+	require.Equal(t, testAccAddress.String(), ret.Bech32Address)
+
+	q = testQuerier("cosmos-hub", SDKv44, newFakeConn(map[string]interface{}{
+		accountMethod: status.Errorf(codes.NotFound, "account %s not found", testAccAddress),
+	}))
+
+	_, err = q.AccountNumbers(context.Background(), addr)
+	require.Equal(t, KindNotFound, ClassifyError(err).Kind)
+
+	// Only the NotFound code means the account doesn't exist, whatever the
+	// message of other failures.
+	q = testQuerier("cosmos-hub", SDKv44, newFakeConn(map[string]interface{}{
+		accountMethod: status.Error(codes.Unavailable, "upstream not found"),
+	}))
+
+	_, err = q.AccountNumbers(context.Background(), addr)
+	require.Equal(t, KindUpstreamUnavailable, ClassifyError(err).Kind)
+}
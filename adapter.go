@@ -0,0 +1,174 @@
+package sdkservice
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	sdkutilities "github.com/emerishq/sdk-service-meta/gen/sdk_utilities"
+	"google.golang.org/grpc"
+)
+
+// Cosmos-sdk generations supported by the service, as set in the
+// sdk_version field of the chain registry.
+const (
+	SDKv42 = "v42"
+	SDKv44 = "v44"
+)
+
+// sdkAdapter holds what differs between cosmos-sdk generations, everything
+// else is handled by chainQuerier. Adapters return upstream errors as is.
+type sdkAdapter interface {
+	// Codec returns the codec decoding the transactions and accounts of the
+	// generation.
+	Codec() codec.Codec
+
+	// NewInterfaceRegistry returns a new registry holding the types of
+	// Codec, to which the codec extensions of chains add their own.
+	NewInterfaceRegistry() codectypes.InterfaceRegistry
+
+	// TotalSupply returns the page of the total supply starting at pageKey.
+	// Its pagination is nil if the generation doesn't paginate the supply.
+	TotalSupply(ctx context.Context, grpcConn grpc.ClientConnInterface, pageKey []byte) (*bank.QueryTotalSupplyResponse, error)
+
+	// Simulate runs the transaction txBytes through the simulator.
+	Simulate(ctx context.Context, grpcConn grpc.ClientConnInterface, txBytes []byte) (*sdktx.SimulateResponse, error)
+
+	// TxNotFound tells whether err is returned by the node for a transaction
+	// which isn't in a block yet.
+	TxNotFound(err error) bool
+
+	// Hooks returns the queries chainName answers with its own modules.
+	Hooks(chainName string) chainHooks
+}
+
+// chainHooks are the queries which depend on the modules a chain runs.
+// Nil mint hooks fall back to the cosmos-sdk mint module, other nil hooks
+// mean the chain doesn't run the module. Chains lacking some of the mint
+// queries set their hooks to notPresent.
+type chainHooks struct {
+	MintInflation       moduleQuery
+	MintParams          moduleQuery
+	MintAnnualProvision moduleQuery
+	MintEpochProvisions moduleQuery
+	BudgetParams        moduleQuery
+	OsmoPools           moduleQuery
+	CrescentPools       moduleQuery
+
+	// EmoneyInflation returns the state of the inflation module of e-money.
+	EmoneyInflation func(ctx context.Context, grpcConn grpc.ClientConnInterface) (*sdkutilities.EmoneyState, error)
+
+	// Fees returns the fees charged on top of gas, like the Terra tax.
+	Fees func(ctx context.Context, chain Chain, txBytes []byte) ([]*sdkutilities.Coin, error)
+}
+
+var sdkAdapters = map[string]sdkAdapter{
+	SDKv42: sdkV42{},
+	SDKv44: sdkV44{},
+}
+
+// defaultSDKVersion is used when no chain is involved, like decoding
+// transactions.
+const defaultSDKVersion = SDKv44
+
+// SupportedSDKVersions returns the cosmos-sdk generations supported by the
+// service.
+func SupportedSDKVersions() []string {
+	ret := make([]string, 0, len(sdkAdapters))
+	for v := range sdkAdapters {
+		ret = append(ret, v)
+	}
+
+	sort.Strings(ret)
+
+	return ret
+}
+
+// sdkVersions resolves the adapter of each chain, either from the chain
+// registry or by asking one of its nodes which cosmos-sdk version it runs.
+type sdkVersions struct {
+	mu       sync.Mutex
+	detected map[string]string
+}
+
+func newSDKVersions() *sdkVersions {
+	return &sdkVersions{
+		detected: map[string]string{},
+	}
+}
+
+// adapter returns the adapter of chain, reachable through grpcConn.
+// Detected versions are kept for the lifetime of the service.
+func (v *sdkVersions) adapter(ctx context.Context, chain Chain, grpcConn grpc.ClientConnInterface) (sdkAdapter, error) {
+	if chain.SDKVersion != "" {
+		return sdkAdapters[chain.SDKVersion], nil
+	}
+
+	v.mu.Lock()
+	version, ok := v.detected[chain.Name]
+	v.mu.Unlock()
+
+	if !ok {
+		var err error
+		version, err = detectSDKVersion(ctx, grpcConn)
+		if err != nil {
+			return nil, fmt.Errorf("chain %s: %w", chain.Name, err)
+		}
+
+		v.mu.Lock()
+		v.detected[chain.Name] = version
+		v.mu.Unlock()
+	}
+
+	return sdkAdapters[version], nil
+}
+
+// detectSDKVersion returns the cosmos-sdk generation run by the node behind
+// grpcConn.
+func detectSDKVersion(ctx context.Context, grpcConn grpc.ClientConnInterface) (string, error) {
+	info, err := tmservice.NewServiceClient(grpcConn).GetNodeInfo(ctx, &tmservice.GetNodeInfoRequest{})
+	if err != nil {
+		return "", fmt.Errorf("cannot detect cosmos-sdk version, %w", err)
+	}
+
+	var sdkVersion string
+	if info.ApplicationVersion != nil {
+		sdkVersion = info.ApplicationVersion.CosmosSdkVersion
+	}
+
+	return sdkGeneration(sdkVersion)
+}
+
+// sdkGeneration maps a cosmos-sdk version, like v0.45.1, to the generation
+// implementing it.
+func sdkGeneration(sdkVersion string) (string, error) {
+	unsupported := newError(
+		KindUnsupportedChain,
+		fmt.Errorf("unsupported cosmos-sdk version %q, set sdk_version in the chain registry", sdkVersion),
+	)
+
+	parts := strings.SplitN(strings.TrimPrefix(sdkVersion, "v"), ".", 3)
+	if len(parts) < 2 || parts[0] != "0" {
+		return "", unsupported
+	}
+
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil || minor < 40 {
+		return "", unsupported
+	}
+
+	// Simulate by tx bytes and paginated total supply came with v0.43.
+	if minor < 43 {
+		return SDKv42, nil
+	}
+
+	return SDKv44, nil
+}
@@ -0,0 +1,94 @@
+package sdkservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSDKGeneration(t *testing.T) {
+	for version, want := range map[string]string{
+		"v0.40.0":     SDKv42,
+		"v0.40.0-rc3": SDKv42,
+		"v0.41.4":     SDKv42,
+		"0.42.11":     SDKv42,
+		"v0.43.0":     SDKv44,
+		"v0.44.5":     SDKv44,
+		"0.45.1":      SDKv44,
+		"v0.45.4-ibc": SDKv44,
+		"v0.45":       SDKv44,
+	} {
+		got, err := sdkGeneration(version)
+		require.NoError(t, err, version)
+		require.Equal(t, want, got, version)
+	}
+
+	for _, version := range []string{
+		"",
+		"v",
+		"v0",
+		"v0.39.2",
+		"v1.0.0",
+		"v0.x.1",
+		"v0.45-ibc",
+		"cosmos-sdk",
+	} {
+		_, err := sdkGeneration(version)
+		require.Equal(t, KindUnsupportedChain, ClassifyError(err).Kind, version)
+	}
+}
+
+func TestSDKVersionsAdapter(t *testing.T) {
+	conn := newFakeConn(map[string]interface{}{
+		nodeInfoMethod: &tmservice.GetNodeInfoResponse{
+			ApplicationVersion: &tmservice.VersionInfo{CosmosSdkVersion: "v0.42.10"},
+		},
+	})
+	v := newSDKVersions()
+	ctx := context.Background()
+
+	// The version set in the registry is used without asking the nodes.
+	a, err := v.adapter(ctx, Chain{Name: "osmosis", SDKVersion: SDKv44}, conn)
+	require.NoError(t, err)
+	require.Equal(t, sdkAdapters[SDKv44], a)
+	require.Zero(t, conn.called(nodeInfoMethod))
+
+	// Detected versions are asked for once.
+	for i := 0; i < 2; i++ {
+		a, err := v.adapter(ctx, Chain{Name: "cosmos-hub"}, conn)
+		require.NoError(t, err)
+		require.Equal(t, sdkAdapters[SDKv42], a)
+	}
+	require.Equal(t, 1, conn.called(nodeInfoMethod))
+}
+
+func TestSDKVersionsDetectionFailure(t *testing.T) {
+	failing := newFakeConn(map[string]interface{}{
+		nodeInfoMethod: status.Error(codes.Unavailable, "connection refused"),
+	})
+	v := newSDKVersions()
+	ctx := context.Background()
+
+	_, err := v.adapter(ctx, Chain{Name: "cosmos-hub"}, failing)
+	require.Error(t, err)
+
+	_, err = v.adapter(ctx, Chain{Name: "cosmos-hub"}, newFakeConn(map[string]interface{}{
+		nodeInfoMethod: &tmservice.GetNodeInfoResponse{
+			ApplicationVersion: &tmservice.VersionInfo{CosmosSdkVersion: "v0.38.0"},
+		},
+	}))
+	require.Equal(t, KindUnsupportedChain, ClassifyError(err).Kind)
+
+	// Failures aren't kept, the nodes are asked again.
+	a, err := v.adapter(ctx, Chain{Name: "cosmos-hub"}, newFakeConn(map[string]interface{}{
+		nodeInfoMethod: &tmservice.GetNodeInfoResponse{
+			ApplicationVersion: &tmservice.VersionInfo{CosmosSdkVersion: "v0.45.1"},
+		},
+	}))
+	require.NoError(t, err)
+	require.Equal(t, sdkAdapters[SDKv44], a)
+}
@@ -0,0 +1,248 @@
+package sdkservice
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"golang.org/x/crypto/sha3"
+)
+
+// Address formats.
+const (
+	FormatHex    = "hex"
+	FormatBech32 = "bech32"
+)
+
+// Address types, derived from the prefix of bech32 addresses.
+const (
+	AddressAccount            = "account"
+	AddressValidatorOperator  = "validator_operator"
+	AddressValidatorConsensus = "validator_consensus"
+)
+
+// Suffixes appended to the account prefix of chains to form the validator
+// operator and consensus ones.
+const (
+	valoperSuffix = "valoper"
+	valconsSuffix = "valcons"
+)
+
+// maxAddressLength is the largest number of bytes in an address.
+const maxAddressLength = 255
+
+// AddressInfo is an address converted to all its forms.
+type AddressInfo struct {
+	Input  string `json:"input"`
+	Format string `json:"format"`
+
+	// Hex holds the lowercase hex encoded bytes of the address, without 0x.
+	Hex string `json:"hex"`
+
+	// Prefix and Type are set for bech32 addresses.
+	Prefix string `json:"prefix,omitempty"`
+	Type   string `json:"type,omitempty"`
+
+	// Bech32 holds the addresses of the same bytes under the requested
+	// prefix, or the account prefix of bech32 addresses.
+	Bech32 *Bech32Addresses `json:"bech32,omitempty"`
+
+	// Chains holds the addresses of the same bytes on every configured
+	// chain having a bech32 prefix.
+	Chains []ChainAddresses `json:"chains"`
+}
+
+// Bech32Addresses holds the account, validator operator and validator
+// consensus bech32 forms of some bytes.
+type Bech32Addresses struct {
+	Prefix             string `json:"prefix"`
+	Account            string `json:"account"`
+	ValidatorOperator  string `json:"validator_operator"`
+	ValidatorConsensus string `json:"validator_consensus"`
+}
+
+// ChainAddresses holds the addresses of some bytes on Chain. Member is true
+// when the converted address is a bech32 one of Chain.
+type ChainAddresses struct {
+	Chain  string `json:"chain"`
+	Member bool   `json:"member"`
+	Bech32Addresses
+}
+
+// ConvertAddress converts address, either bech32 or hex encoded, to bech32
+// with prefix, and to the addresses of chains. Bech32 checksums are always
+// validated, and so are EIP-55 ones of mixed case 0x prefixed hex addresses.
+func ConvertAddress(address string, prefix string, chains []Chain) (AddressInfo, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return AddressInfo{}, newError(KindInvalidArgument, fmt.Errorf("missing address"))
+	}
+
+	ret := AddressInfo{
+		Input:  address,
+		Chains: []ChainAddresses{},
+	}
+
+	hrp, addrBytes, err := bech32.DecodeAndConvert(address)
+	if err == nil {
+		ret.Format = FormatBech32
+		ret.Prefix = hrp
+		ret.Type, _ = addressType(hrp, chains)
+	} else {
+		b, hexErr := hexAddressBytes(address)
+		if hexErr != nil {
+			if strings.ContainsRune(address, '1') && !isHex(address) {
+				return AddressInfo{}, newError(KindInvalidArgument, fmt.Errorf("invalid bech32 address %s, %w", address, err))
+			}
+
+			return AddressInfo{}, newError(KindInvalidArgument, fmt.Errorf("invalid hex address %s, %w", address, hexErr))
+		}
+
+		addrBytes = b
+		ret.Format = FormatHex
+	}
+
+	if len(addrBytes) == 0 || len(addrBytes) > maxAddressLength {
+		return AddressInfo{}, newError(KindInvalidArgument, fmt.Errorf("invalid address length %d", len(addrBytes)))
+	}
+
+	ret.Hex = hex.EncodeToString(addrBytes)
+
+	if prefix == "" && ret.Format == FormatBech32 {
+		_, prefix = addressType(ret.Prefix, chains)
+	}
+
+	if prefix != "" {
+		addrs, err := bech32Addresses(addrBytes, prefix)
+		if err != nil {
+			return AddressInfo{}, err
+		}
+
+		ret.Bech32 = &addrs
+	}
+
+	for _, c := range chains {
+		if c.Bech32Prefix == "" {
+			continue
+		}
+
+		addrs, err := bech32Addresses(addrBytes, c.Bech32Prefix)
+		if err != nil {
+			return AddressInfo{}, err
+		}
+
+		ret.Chains = append(ret.Chains, ChainAddresses{
+			Chain:           c.Name,
+			Member:          ret.Format == FormatBech32 && hasAccountPrefix(ret.Prefix, c.Bech32Prefix),
+			Bech32Addresses: addrs,
+		})
+	}
+
+	return ret, nil
+}
+
+// hexAddressBytes decodes the hex address, with or without 0x. Mixed case
+// addresses with 0x must have a valid EIP-55 checksum.
+func hexAddressBytes(address string) ([]byte, error) {
+	digits := trimHexPrefix(address)
+
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, err
+	}
+
+	mixedCase := strings.ToLower(digits) != digits && strings.ToUpper(digits) != digits
+	if mixedCase && digits != address && eip55(b) != digits {
+		return nil, fmt.Errorf("invalid EIP-55 checksum")
+	}
+
+	return b, nil
+}
+
+func trimHexPrefix(s string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+}
+
+// isHex returns whether s only has hex digits, after an optional 0x.
+func isHex(s string) bool {
+	for _, c := range trimHexPrefix(s) {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// eip55 returns the hex encoding of b with the case of the letters set from
+// its keccak256 hash, as defined by EIP-55.
+func eip55(b []byte) string {
+	lower := hex.EncodeToString(b)
+
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write([]byte(lower))
+	hash := h.Sum(nil)
+
+	ret := []byte(lower)
+	for i, c := range ret {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+
+		if c >= 'a' && nibble >= 8 {
+			ret[i] = c - 'a' + 'A'
+		}
+	}
+
+	return string(ret)
+}
+
+// addressType returns the type of addresses with prefix hrp, and the account
+// prefix it's derived from. The prefixes of chains are used to tell apart
+// account prefixes which end like validator ones.
+func addressType(hrp string, chains []Chain) (string, string) {
+	for _, c := range chains {
+		if c.Bech32Prefix == hrp {
+			return AddressAccount, hrp
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(hrp, valoperSuffix):
+		return AddressValidatorOperator, strings.TrimSuffix(hrp, valoperSuffix)
+	case strings.HasSuffix(hrp, valconsSuffix):
+		return AddressValidatorConsensus, strings.TrimSuffix(hrp, valconsSuffix)
+	default:
+		return AddressAccount, hrp
+	}
+}
+
+// hasAccountPrefix returns whether hrp is the account, validator operator or
+// validator consensus prefix derived from prefix.
+func hasAccountPrefix(hrp string, prefix string) bool {
+	return hrp == prefix || hrp == prefix+valoperSuffix || hrp == prefix+valconsSuffix
+}
+
+func bech32Addresses(addrBytes []byte, prefix string) (Bech32Addresses, error) {
+	ret := Bech32Addresses{Prefix: prefix}
+
+	for _, a := range []struct {
+		hrp  string
+		addr *string
+	}{
+		{prefix, &ret.Account},
+		{prefix + valoperSuffix, &ret.ValidatorOperator},
+		{prefix + valconsSuffix, &ret.ValidatorConsensus},
+	} {
+		addr, err := bech32.ConvertAndEncode(a.hrp, addrBytes)
+		if err != nil {
+			return Bech32Addresses{}, newError(KindInvalidArgument, fmt.Errorf("cannot encode address with prefix %s, %w", a.hrp, err))
+		}
+
+		*a.addr = addr
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,133 @@
+package sdkservice
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testAddressHex holds the bytes 1 to 20, and testAddressBech32 its cosmos
+// address.
+const (
+	testAddressHex    = "0102030405060708090a0b0c0d0e0f1011121314"
+	testAddressBech32 = "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+)
+
+func TestEIP55(t *testing.T) {
+	// Test vectors of EIP-55.
+	for _, addr := range []string{
+		"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"fB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"dbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"D1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		b, err := hex.DecodeString(addr)
+		require.NoError(t, err)
+		require.Equal(t, addr, eip55(b))
+
+		info, err := ConvertAddress("0x"+addr, "", nil)
+		require.NoError(t, err, addr)
+		require.Equal(t, FormatHex, info.Format)
+		require.Equal(t, strings.ToLower(addr), info.Hex)
+	}
+
+	for _, addr := range []string{
+		// A single letter with the wrong case.
+		"0x5aaeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0XfB6916095ca1df60bB79Ce92cE3Ea74c37c5d35A",
+	} {
+		_, err := ConvertAddress(addr, "", nil)
+		require.Equal(t, KindInvalidArgument, ClassifyError(err).Kind, addr)
+		require.Contains(t, err.Error(), "EIP-55", addr)
+	}
+
+	// Single case addresses, and ones without 0x, carry no checksum.
+	for _, addr := range []string{
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED",
+		"5aaeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	} {
+		info, err := ConvertAddress(addr, "", nil)
+		require.NoError(t, err, addr)
+		require.Equal(t, "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", info.Hex, addr)
+	}
+}
+
+func TestConvertAddress(t *testing.T) {
+	chains := []Chain{
+		{Name: "cosmos-hub", Bech32Prefix: "cosmos"},
+		{Name: "osmosis", Bech32Prefix: "osmo"},
+		{Name: "no-prefix"},
+	}
+
+	cosmos, err := bech32Addresses(mustHex(t, testAddressHex), "cosmos")
+	require.NoError(t, err)
+	require.Equal(t, testAddressBech32, cosmos.Account)
+	require.True(t, strings.HasPrefix(cosmos.ValidatorOperator, "cosmosvaloper1"))
+	require.True(t, strings.HasPrefix(cosmos.ValidatorConsensus, "cosmosvalcons1"))
+
+	osmo, err := bech32Addresses(mustHex(t, testAddressHex), "osmo")
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		input string
+		typ   string
+	}{
+		{cosmos.Account, AddressAccount},
+		{cosmos.ValidatorOperator, AddressValidatorOperator},
+		{cosmos.ValidatorConsensus, AddressValidatorConsensus},
+	} {
+		info, err := ConvertAddress(tt.input, "", chains)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, FormatBech32, info.Format, tt.input)
+		require.Equal(t, tt.typ, info.Type, tt.input)
+		require.Equal(t, testAddressHex, info.Hex, tt.input)
+
+		// Validator addresses convert to the account prefix they derive
+		// from.
+		require.Equal(t, &cosmos, info.Bech32, tt.input)
+
+		require.Equal(t, []ChainAddresses{
+			{Chain: "cosmos-hub", Member: true, Bech32Addresses: cosmos},
+			{Chain: "osmosis", Member: false, Bech32Addresses: osmo},
+		}, info.Chains, tt.input)
+	}
+
+	info, err := ConvertAddress(testAddressHex, "osmo", chains)
+	require.NoError(t, err)
+	require.Equal(t, FormatHex, info.Format)
+	require.Empty(t, info.Type)
+	require.Equal(t, &osmo, info.Bech32)
+	require.False(t, info.Chains[0].Member)
+
+	// Hex addresses without a prefix only convert to the chain ones.
+	info, err = ConvertAddress("0x"+testAddressHex, "", chains)
+	require.NoError(t, err)
+	require.Nil(t, info.Bech32)
+	require.Len(t, info.Chains, 2)
+}
+
+func TestConvertAddressInvalid(t *testing.T) {
+	for _, addr := range []string{
+		"",
+		"  ",
+		// Bad checksum.
+		testAddressBech32[:len(testAddressBech32)-1] + "a",
+		"0x0102zz",
+		"0x",
+		strings.Repeat("ab", maxAddressLength+1),
+	} {
+		_, err := ConvertAddress(addr, "", nil)
+		require.Error(t, err, addr)
+		require.Equal(t, KindInvalidArgument, ClassifyError(err).Kind, addr)
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
@@ -0,0 +1,213 @@
+package sdkservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	sdkquery "github.com/cosmos/cosmos-sdk/types/query"
+	vesting "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc/codes"
+)
+
+// spendableBalancesMethod was added to the bank module in v0.46, after the
+// version this service is built with. Its request and response have the
+// same fields as the ones of AllBalances, which are used to encode them.
+const spendableBalancesMethod = "/cosmos.bank.v1beta1.Query/SpendableBalances"
+
+// balancesPageSize is the number of balances fetched per upstream request.
+const balancesPageSize = 500
+
+// Balances holds the balances of an address, per denom.
+type Balances struct {
+	Address  string         `json:"address"`
+	Balances []DenomBalance `json:"balances"`
+}
+
+// DenomBalance is the balance of an address in Denom. Locked is the part of
+// Total which can't be spent yet, because it's vesting.
+type DenomBalance struct {
+	Denom     string `json:"denom"`
+	Total     string `json:"total"`
+	Spendable string `json:"spendable"`
+	Locked    string `json:"locked"`
+}
+
+// AccountAddress identifies an account either by its bech32 address, or by
+// its hex encoded bytes and a bech32 prefix. The prefix defaults to the one
+// configured for the chain, and must match it when both are set.
+type AccountAddress struct {
+	Bech32       string
+	Hex          string
+	Bech32Prefix string
+}
+
+// bech32Address returns the bech32 form of address on the chain of q,
+// checking that its prefix is the one of the chain.
+func (q *chainQuerier) bech32Address(address AccountAddress) (string, error) {
+	prefix := address.Bech32Prefix
+	if configured := q.chain.Bech32Prefix; configured != "" {
+		if prefix != "" && prefix != configured {
+			return "", newError(KindInvalidArgument, fmt.Errorf("bech32 prefix %s doesn't match the prefix %s of chain %s", prefix, configured, q.chain.Name))
+		}
+
+		prefix = configured
+	}
+
+	if address.Bech32 == "" {
+		if address.Hex == "" {
+			return "", newError(KindInvalidArgument, fmt.Errorf("missing address"))
+		}
+
+		if prefix == "" {
+			return "", newError(KindInvalidArgument, fmt.Errorf("missing bech32 prefix, none is configured for chain %s", q.chain.Name))
+		}
+
+		return bech32Address(address.Hex, prefix)
+	}
+
+	if address.Hex != "" {
+		return "", newError(KindInvalidArgument, fmt.Errorf("either a bech32 or a hex address must be given, not both"))
+	}
+
+	hrp, _, err := bech32.DecodeAndConvert(address.Bech32)
+	if err != nil {
+		return "", newError(KindInvalidArgument, fmt.Errorf("invalid bech32 address %s, %w", address.Bech32, err))
+	}
+
+	if prefix != "" && prefix != hrp {
+		return "", newError(KindInvalidArgument, fmt.Errorf("address %s doesn't have prefix %s", address.Bech32, prefix))
+	}
+
+	return address.Bech32, nil
+}
+
+// Balances returns the total, spendable and locked balances of address.
+func (q *chainQuerier) Balances(ctx context.Context, address AccountAddress) (Balances, error) {
+	addr, err := q.bech32Address(address)
+	if err != nil {
+		return Balances{}, err
+	}
+
+	total, err := q.allBalances(ctx, addr, "/cosmos.bank.v1beta1.Query/AllBalances")
+	if err != nil {
+		return Balances{}, err
+	}
+
+	spendable, err := q.allBalances(ctx, addr, spendableBalancesMethod)
+	if grpcCode(err) == codes.Unimplemented {
+		spendable, err = q.vestingSpendable(ctx, addr, total)
+	}
+
+	if err != nil {
+		return Balances{}, err
+	}
+
+	ret := Balances{
+		Address:  addr,
+		Balances: []DenomBalance{},
+	}
+
+	for _, c := range total {
+		s := spendable.AmountOf(c.Denom)
+		locked := c.Amount.Sub(s)
+		if locked.IsNegative() {
+			locked = sdktypes.ZeroInt()
+		}
+
+		ret.Balances = append(ret.Balances, DenomBalance{
+			Denom:     c.Denom,
+			Total:     c.Amount.String(),
+			Spendable: s.String(),
+			Locked:    locked.String(),
+		})
+	}
+
+	return ret, nil
+}
+
+// allBalances returns all the pages of balances of addr returned by method,
+// which takes and returns the AllBalances messages.
+func (q *chainQuerier) allBalances(ctx context.Context, addr string, method string) (sdktypes.Coins, error) {
+	ret := sdktypes.NewCoins()
+
+	var key []byte
+	for {
+		res := &bank.QueryAllBalancesResponse{}
+
+		err := q.conn.Invoke(ctx, method, &bank.QueryAllBalancesRequest{
+			Address: addr,
+			Pagination: &sdkquery.PageRequest{
+				Key:   key,
+				Limit: balancesPageSize,
+			},
+		}, res)
+		if err != nil {
+			if grpcCode(err) == codes.Unimplemented {
+				return nil, err
+			}
+
+			return nil, queryError("bank", err)
+		}
+
+		ret = ret.Add(res.Balances...)
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return ret, nil
+		}
+
+		key = res.Pagination.NextKey
+	}
+}
+
+// vestingSpendable returns the spendable part of total for nodes which don't
+// have the SpendableBalances query, from the vesting schedule of the account
+// at the time of the latest block.
+func (q *chainQuerier) vestingSpendable(ctx context.Context, addr string, total sdktypes.Coins) (sdktypes.Coins, error) {
+	account, err := q.account(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	va, ok := account.(vesting.VestingAccount)
+	if !ok {
+		return total, nil
+	}
+
+	blockTime, err := q.latestBlockTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spendable, hasNeg := total.SafeSub(va.LockedCoins(blockTime))
+	if hasNeg {
+		// Only keep the denoms which aren't entirely locked.
+		ret := sdktypes.NewCoins()
+		for _, c := range spendable {
+			if c.IsPositive() {
+				ret = ret.Add(c)
+			}
+		}
+
+		return ret, nil
+	}
+
+	return spendable, nil
+}
+
+func (q *chainQuerier) latestBlockTime(ctx context.Context) (time.Time, error) {
+	res, err := tmservice.NewServiceClient(q.conn).GetLatestBlock(ctx, &tmservice.GetLatestBlockRequest{})
+	if err != nil {
+		return time.Time{}, queryError("tendermint", err)
+	}
+
+	if res.Block == nil {
+		return time.Time{}, fmt.Errorf("latest block has no header")
+	}
+
+	return res.Block.Header.Time, nil
+}
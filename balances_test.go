@@ -0,0 +1,240 @@
+package sdkservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkquery "github.com/cosmos/cosmos-sdk/types/query"
+	auth "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	gogoproto "github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+)
+
+const (
+	allBalancesMethod    = "/cosmos.bank.v1beta1.Query/AllBalances"
+	accountMethod        = "/cosmos.auth.v1beta1.Query/Account"
+	latestBlockMethod    = "/cosmos.base.tendermint.v1beta1.Service/GetLatestBlock"
+	testVestingBlockTime = 1650000000
+)
+
+var testAccAddress = sdktypes.AccAddress([]byte("vesting-account-addr"))
+
+// vestingConn returns a connection to nodes without the SpendableBalances
+// query, holding total for the account and answering its account query with
+// account.
+func vestingConn(t *testing.T, total sdktypes.Coins, account gogoproto.Message) *fakeConn {
+	a, err := codectypes.NewAnyWithValue(account)
+	require.NoError(t, err)
+
+	// Balances are returned in two pages.
+	pages := []*bank.QueryAllBalancesResponse{
+		{Balances: total[:1], Pagination: &sdkquery.PageResponse{NextKey: []byte("next")}},
+		{Balances: total[1:]},
+	}
+
+	return newFakeConn(map[string]interface{}{
+		allBalancesMethod: fakeReply(func(args interface{}) (interface{}, error) {
+			if len(args.(*bank.QueryAllBalancesRequest).Pagination.Key) == 0 {
+				return pages[0], nil
+			}
+
+			return pages[1], nil
+		}),
+		accountMethod: &auth.QueryAccountResponse{Account: a},
+		latestBlockMethod: &tmservice.GetLatestBlockResponse{
+			Block: &tmproto.Block{Header: tmproto.Header{Time: time.Unix(testVestingBlockTime, 0)}},
+		},
+	})
+}
+
+func baseVestingAccount() *auth.BaseAccount {
+	return auth.NewBaseAccount(testAccAddress, nil, 4, 2)
+}
+
+func atoms(amount int64) sdktypes.Coins {
+	return sdktypes.NewCoins(sdktypes.NewInt64Coin("uatom", amount))
+}
+
+func TestBalancesVestingFallback(t *testing.T) {
+	const now = testVestingBlockTime
+	total := sdktypes.NewCoins(sdktypes.NewInt64Coin("uatom", 100), sdktypes.NewInt64Coin("uosmo", 5))
+
+	delegated := vestingtypes.NewContinuousVestingAccount(baseVestingAccount(), atoms(100), now-50, now+50)
+	delegated.DelegatedVesting = atoms(30)
+
+	tests := []struct {
+		name      string
+		account   gogoproto.Message
+		total     sdktypes.Coins
+		spendable string
+		locked    string
+	}{
+		{
+			name:      "base account",
+			account:   baseVestingAccount(),
+			spendable: "100",
+			locked:    "0",
+		},
+		{
+			name:      "continuous vesting half way",
+			account:   vestingtypes.NewContinuousVestingAccount(baseVestingAccount(), atoms(100), now-50, now+50),
+			spendable: "50",
+			locked:    "50",
+		},
+		{
+			name:      "continuous vesting ended",
+			account:   vestingtypes.NewContinuousVestingAccount(baseVestingAccount(), atoms(100), now-100, now-50),
+			spendable: "100",
+			locked:    "0",
+		},
+		{
+			name:      "delayed vesting",
+			account:   vestingtypes.NewDelayedVestingAccount(baseVestingAccount(), atoms(100), now+10),
+			spendable: "0",
+			locked:    "100",
+		},
+		{
+			name: "periodic vesting",
+			account: vestingtypes.NewPeriodicVestingAccount(baseVestingAccount(), atoms(100), now-15, vestingtypes.Periods{
+				{Length: 10, Amount: atoms(40)},
+				{Length: 10, Amount: atoms(60)},
+			}),
+			spendable: "40",
+			locked:    "60",
+		},
+		{
+			name:      "permanently locked",
+			account:   vestingtypes.NewPermanentLockedAccount(baseVestingAccount(), atoms(100)),
+			spendable: "0",
+			locked:    "100",
+		},
+		{
+			// Delegated vesting coins left the bank balance, 50 are still
+			// vesting of which 30 are delegated.
+			name:      "delegated vesting",
+			account:   delegated,
+			total:     sdktypes.NewCoins(sdktypes.NewInt64Coin("uatom", 70), sdktypes.NewInt64Coin("uosmo", 5)),
+			spendable: "50",
+			locked:    "20",
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.total == nil {
+			tt.total = total
+		}
+
+		conn := vestingConn(t, tt.total, tt.account)
+		q := testQuerier("cosmos-hub", SDKv44, conn)
+
+		ret, err := q.Balances(context.Background(), AccountAddress{Bech32: testAccAddress.String()})
+		require.NoError(t, err, tt.name)
+		require.Equal(t, 1, conn.called(spendableBalancesMethod), tt.name)
+		require.Equal(t, 2, conn.called(allBalancesMethod), tt.name)
+
+		atom := ret.Balances[0]
+		require.Equal(t, "uatom", atom.Denom, tt.name)
+		require.Equal(t, tt.total.AmountOf("uatom").String(), atom.Total, tt.name)
+		require.Equal(t, tt.spendable, atom.Spendable, tt.name)
+		require.Equal(t, tt.locked, atom.Locked, tt.name)
+
+		// Denoms which aren't vesting stay spendable.
+		require.Equal(t, DenomBalance{Denom: "uosmo", Total: "5", Spendable: "5", Locked: "0"}, ret.Balances[1], tt.name)
+	}
+}
+
+func TestBalancesSpendableQuery(t *testing.T) {
+	// Nodes with the SpendableBalances query answer it, the account isn't
+	// queried.
+	conn := newFakeConn(map[string]interface{}{
+		allBalancesMethod:       &bank.QueryAllBalancesResponse{Balances: atoms(100)},
+		spendableBalancesMethod: &bank.QueryAllBalancesResponse{Balances: atoms(25)},
+	})
+	q := testQuerier("cosmos-hub", SDKv44, conn)
+
+	ret, err := q.Balances(context.Background(), AccountAddress{Bech32: testAccAddress.String()})
+	require.NoError(t, err)
+	require.Equal(t, []DenomBalance{{Denom: "uatom", Total: "100", Spendable: "25", Locked: "75"}}, ret.Balances)
+	require.Zero(t, conn.called(accountMethod))
+}
+
+func TestBalancesAddress(t *testing.T) {
+	const (
+		hex    = "0102030405060708090a0b0c0d0e0f1011121314"
+		bech32 = "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+		osmo   = "osmo1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5wgd3ma"
+	)
+
+	configured := testQuerier("cosmos-hub", SDKv44, newFakeConn(nil))
+	configured.chain.Bech32Prefix = "cosmos"
+	unconfigured := testQuerier("cosmos-hub", SDKv44, newFakeConn(nil))
+
+	for name, tt := range map[string]struct {
+		q       *chainQuerier
+		address AccountAddress
+	}{
+		"hex":                       {configured, AccountAddress{Hex: hex}},
+		"hex with the chain prefix": {configured, AccountAddress{Hex: hex, Bech32Prefix: "cosmos"}},
+		"hex with a prefix":         {unconfigured, AccountAddress{Hex: hex, Bech32Prefix: "cosmos"}},
+		"bech32":                    {configured, AccountAddress{Bech32: bech32}},
+		"bech32 with the prefix":    {configured, AccountAddress{Bech32: bech32, Bech32Prefix: "cosmos"}},
+		"bech32, no chain prefix":   {unconfigured, AccountAddress{Bech32: bech32}},
+	} {
+		addr, err := tt.q.bech32Address(tt.address)
+		require.NoError(t, err, name)
+		require.Equal(t, bech32, addr, name)
+	}
+
+	for name, tt := range map[string]struct {
+		q       *chainQuerier
+		address AccountAddress
+	}{
+		"missing address":           {configured, AccountAddress{Bech32Prefix: "cosmos"}},
+		"missing prefix":            {unconfigured, AccountAddress{Hex: hex}},
+		"both addresses":            {configured, AccountAddress{Hex: hex, Bech32: bech32}},
+		"prefix of another chain":   {configured, AccountAddress{Hex: hex, Bech32Prefix: "osmo"}},
+		"address of another chain":  {configured, AccountAddress{Bech32: osmo}},
+		"address of another prefix": {unconfigured, AccountAddress{Bech32: osmo, Bech32Prefix: "cosmos"}},
+		"invalid hex":               {configured, AccountAddress{Hex: "0102zz"}},
+		"invalid bech32":            {configured, AccountAddress{Bech32: bech32[:len(bech32)-1] + "a"}},
+	} {
+		_, err := tt.q.bech32Address(tt.address)
+		require.Error(t, err, name)
+		require.Equal(t, KindInvalidArgument, ClassifyError(err).Kind, name)
+	}
+
+	// Addresses are checked before querying the nodes.
+	conn := newFakeConn(nil)
+	q := testQuerier("cosmos-hub", SDKv44, conn)
+	q.chain.Bech32Prefix = "cosmos"
+
+	_, err := q.Balances(context.Background(), AccountAddress{Bech32: osmo})
+	require.Equal(t, KindInvalidArgument, ClassifyError(err).Kind)
+	require.Zero(t, conn.called(allBalancesMethod))
+}
+
+func TestPayloadAddress(t *testing.T) {
+	hex, prefix := "0102", "cosmos"
+
+	require.Equal(t, AccountAddress{}, payloadAddress(nil, nil))
+	require.Equal(t, AccountAddress{Hex: hex, Bech32Prefix: prefix}, payloadAddress(&hex, &prefix))
+}
+
+func TestChainBech32Prefix(t *testing.T) {
+	for _, prefix := range []string{"", "cosmos", "osmo"} {
+		_, err := NewChainRegistry([]Chain{{Name: "cosmos-hub", GRPC: []string{"localhost:9090"}, Bech32Prefix: prefix}})
+		require.NoError(t, err, prefix)
+	}
+
+	for _, prefix := range []string{"Cosmos", "COSMOS", "cos mos", "cosmos\x7f"} {
+		_, err := NewChainRegistry([]Chain{{Name: "cosmos-hub", GRPC: []string{"localhost:9090"}, Bech32Prefix: prefix}})
+		require.Error(t, err, prefix)
+	}
+}
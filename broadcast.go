@@ -0,0 +1,280 @@
+package sdkservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/tendermint/tendermint/abci/types"
+	"google.golang.org/grpc/codes"
+)
+
+// Broadcast modes.
+const (
+	// BroadcastAsync returns as soon as the transaction is sent to the node.
+	BroadcastAsync = "async"
+
+	// BroadcastSync returns once the transaction passed CheckTx.
+	BroadcastSync = "sync"
+
+	// BroadcastCommit returns once the transaction is included in a block.
+	BroadcastCommit = "commit"
+)
+
+var broadcastModes = map[string]sdktx.BroadcastMode{
+	BroadcastAsync:  sdktx.BroadcastMode_BROADCAST_MODE_ASYNC,
+	BroadcastSync:   sdktx.BroadcastMode_BROADCAST_MODE_SYNC,
+	BroadcastCommit: sdktx.BroadcastMode_BROADCAST_MODE_BLOCK,
+}
+
+// DefaultInclusionTimeout is the amount of time to wait for a transaction to
+// be included in a block when no timeout is given.
+const DefaultInclusionTimeout = 30 * time.Second
+
+// MaxInclusionTimeout is the longest amount of time to wait for a
+// transaction to be included in a block.
+const MaxInclusionTimeout = 5 * time.Minute
+
+// inclusionPollInterval is the interval between lookups of a transaction
+// waited for.
+const inclusionPollInterval = time.Second
+
+// BroadcastOptions tells how to broadcast a transaction.
+type BroadcastOptions struct {
+	// Mode is one of BroadcastAsync, BroadcastSync and BroadcastCommit,
+	// defaults to BroadcastSync.
+	Mode string
+
+	// WaitForInclusion makes the broadcast return once the transaction is
+	// included in a block, or Timeout elapsed.
+	WaitForInclusion bool
+
+	// Timeout defaults to DefaultInclusionTimeout.
+	Timeout time.Duration
+}
+
+// BroadcastResult is the outcome of a transaction broadcast, holding the
+// transaction response of the node.
+type BroadcastResult struct {
+	Hash string `json:"hash"`
+
+	// Included tells whether the transaction is known to be in a block, in
+	// which case Height, Code, gas and Events are the ones of its execution.
+	// Otherwise they're the ones of CheckTx, when the mode waits for it.
+	Included bool `json:"included"`
+
+	Height    int64   `json:"height,omitempty"`
+	Code      uint32  `json:"code"`
+	Codespace string  `json:"codespace,omitempty"`
+	RawLog    string  `json:"raw_log,omitempty"`
+	Info      string  `json:"info,omitempty"`
+	Data      string  `json:"data,omitempty"`
+	Timestamp string  `json:"timestamp,omitempty"`
+	GasWanted int64   `json:"gas_wanted"`
+	GasUsed   int64   `json:"gas_used"`
+	Events    []Event `json:"events"`
+
+	// Failure is set when Code isn't OK, either because CheckTx rejected the
+	// transaction or because it failed in its block.
+	Failure *TxFailure `json:"failure,omitempty"`
+}
+
+// Event is an event emitted by a transaction.
+type Event struct {
+	Type       string           `json:"type"`
+	Attributes []EventAttribute `json:"attributes"`
+}
+
+// EventAttribute is a key and value of an Event.
+type EventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// validate checks o and applies its defaults.
+func (o *BroadcastOptions) validate() error {
+	if o.Mode == "" {
+		o.Mode = BroadcastSync
+	}
+
+	if _, ok := broadcastModes[o.Mode]; !ok {
+		return newError(KindInvalidArgument, fmt.Errorf("unknown broadcast mode %q", o.Mode))
+	}
+
+	switch {
+	case o.Timeout < 0 || o.Timeout > MaxInclusionTimeout:
+		return newError(KindInvalidArgument, fmt.Errorf("inclusion timeout must be between 0 and %s", MaxInclusionTimeout))
+	case o.Timeout == 0:
+		o.Timeout = DefaultInclusionTimeout
+	}
+
+	return nil
+}
+
+// Broadcast sends txBytes to the node, and waits for its inclusion if asked
+// to. Failed transactions, either rejected by CheckTx or included in a block,
+// aren't errors: they're returned with their code and Failure set.
+func (q *chainQuerier) Broadcast(ctx context.Context, txBytes []byte, opts BroadcastOptions) (BroadcastResult, error) {
+	if err := opts.validate(); err != nil {
+		return BroadcastResult{}, err
+	}
+
+	txClient := sdktx.NewServiceClient(q.conn)
+	grpcRes, err := txClient.BroadcastTx(
+		ctx,
+		&sdktx.BroadcastTxRequest{
+			Mode:    broadcastModes[opts.Mode],
+			TxBytes: txBytes, // Proto-binary of the signed transaction.
+		},
+	)
+
+	if err != nil {
+		return BroadcastResult{}, fmt.Errorf("cannot broadcast transaction, %w", err)
+	}
+
+	if grpcRes.TxResponse == nil {
+		return BroadcastResult{}, newError(KindUpstreamUnavailable, fmt.Errorf("node returned no transaction response"))
+	}
+
+	ret := broadcastResult(grpcRes.TxResponse)
+	if !opts.WaitForInclusion || ret.Included || ret.Failure != nil {
+		return ret, nil
+	}
+
+	included, err := q.waitForTx(ctx, ret.Hash, opts.Timeout)
+	if err != nil || included == nil {
+		return ret, err
+	}
+
+	return broadcastResult(included), nil
+}
+
+// waitForTx looks hash up until it's found or timeout elapsed, in which case
+// it returns nil. Lookups failing for a reason which may go away, like the
+// nodes being unavailable, are retried until then.
+func (q *chainQuerier) waitForTx(ctx context.Context, hash string, timeout time.Duration) (*sdktypes.TxResponse, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(inclusionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		res, err := q.lookupTx(waitCtx, hash)
+		switch {
+		case err == nil && res != nil:
+			return res, nil
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case waitCtx.Err() != nil:
+			return nil, nil
+		case err != nil && !ClassifyError(err).temporary():
+			return nil, err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			return nil, nil
+		}
+	}
+}
+
+// lookupTx returns the response of the transaction hash, or nil if it isn't
+// in a block yet.
+func (q *chainQuerier) lookupTx(ctx context.Context, hash string) (*sdktypes.TxResponse, error) {
+	txClient := sdktx.NewServiceClient(q.conn)
+
+	res, err := txClient.GetTx(ctx, &sdktx.GetTxRequest{Hash: hash})
+	switch {
+	case err == nil:
+		return res.TxResponse, nil
+	case q.sdk.TxNotFound(err):
+		return nil, nil
+	default:
+		return nil, queryError("tx", err)
+	}
+}
+
+// isTxNotFound tells whether err is returned for a transaction which isn't
+// in a block yet.
+func isTxNotFound(err error) bool {
+	return grpcCode(err) == codes.NotFound
+}
+
+func broadcastResult(res *sdktypes.TxResponse) BroadcastResult {
+	ret := BroadcastResult{
+		Hash:      res.TxHash,
+		Included:  res.Height > 0,
+		Height:    res.Height,
+		Code:      res.Code,
+		Codespace: res.Codespace,
+		RawLog:    res.RawLog,
+		Info:      res.Info,
+		Data:      res.Data,
+		Timestamp: res.Timestamp,
+		GasWanted: res.GasWanted,
+		GasUsed:   res.GasUsed,
+		Events:    txEvents(res),
+	}
+
+	if res.Code != types.CodeTypeOK {
+		ret.Failure = classifyFailure(res.Codespace, res.Code, res.RawLog)
+	}
+
+	return ret
+}
+
+// Err returns the error matching the failure of r, if any.
+func (r BroadcastResult) Err() error {
+	if r.Failure == nil {
+		return nil
+	}
+
+	e := newSDKError(
+		r.Codespace,
+		r.Code,
+		fmt.Errorf("transaction relaying error: code %d, %s", r.Code, r.RawLog),
+	)
+	e.Reason = r.Failure.Reason
+
+	return e
+}
+
+// txEvents returns the events of res, falling back to the ones of its logs
+// for nodes before v0.43, which only return them there.
+func txEvents(res *sdktypes.TxResponse) []Event {
+	ret := []Event{}
+
+	if len(res.Events) > 0 {
+		for _, e := range res.Events {
+			ev := Event{Type: e.Type, Attributes: []EventAttribute{}}
+			for _, a := range e.Attributes {
+				ev.Attributes = append(ev.Attributes, EventAttribute{Key: string(a.Key), Value: string(a.Value)})
+			}
+
+			ret = append(ret, ev)
+		}
+
+		return ret
+	}
+
+	for _, l := range res.Logs {
+		for _, e := range l.Events {
+			ev := Event{Type: e.Type, Attributes: []EventAttribute{}}
+			for _, a := range e.Attributes {
+				ev.Attributes = append(ev.Attributes, EventAttribute{Key: a.Key, Value: a.Value})
+			}
+
+			ret = append(ret, ev)
+		}
+	}
+
+	return ret
+}
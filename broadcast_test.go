@@ -0,0 +1,108 @@
+package sdkservice
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const broadcastTxMethod = "/cosmos.tx.v1beta1.Service/BroadcastTx"
+
+// sequence returns a fakeReply answering with replies in order, the last one
+// being repeated.
+func sequence(replies ...interface{}) fakeReply {
+	var (
+		mu sync.Mutex
+		i  int
+	)
+
+	return func(interface{}) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		r := replies[i]
+		if i < len(replies)-1 {
+			i++
+		}
+
+		if err, ok := r.(error); ok {
+			return nil, err
+		}
+
+		return r, nil
+	}
+}
+
+func TestBroadcastWaitRetriesTransientErrors(t *testing.T) {
+	conn := newFakeConn(map[string]interface{}{
+		broadcastTxMethod: &sdktx.BroadcastTxResponse{TxResponse: &sdktypes.TxResponse{TxHash: "ABCD"}},
+		getTxMethod: sequence(
+			status.Error(codes.Unavailable, "connection refused"),
+			status.Error(codes.NotFound, "tx not found: ABCD"),
+			&sdktx.GetTxResponse{TxResponse: &sdktypes.TxResponse{TxHash: "ABCD", Height: 42}},
+		),
+	})
+
+	res, err := testQuerier("cosmos-hub", SDKv44, conn).Broadcast(context.Background(), []byte("tx"), BroadcastOptions{
+		WaitForInclusion: true,
+		Timeout:          10 * time.Second,
+	})
+	require.NoError(t, err)
+	require.True(t, res.Included)
+	require.Equal(t, int64(42), res.Height)
+	require.Equal(t, 3, conn.called(getTxMethod))
+}
+
+func TestBroadcastWaitTimeout(t *testing.T) {
+	conn := newFakeConn(map[string]interface{}{
+		broadcastTxMethod: &sdktx.BroadcastTxResponse{TxResponse: &sdktypes.TxResponse{TxHash: "ABCD"}},
+		getTxMethod:       status.Error(codes.Unavailable, "connection refused"),
+	})
+
+	res, err := testQuerier("cosmos-hub", SDKv44, conn).Broadcast(context.Background(), []byte("tx"), BroadcastOptions{
+		WaitForInclusion: true,
+		Timeout:          50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.False(t, res.Included)
+	require.Equal(t, "ABCD", res.Hash)
+}
+
+func TestBroadcastWaitLookupFailure(t *testing.T) {
+	conn := newFakeConn(map[string]interface{}{
+		broadcastTxMethod: &sdktx.BroadcastTxResponse{TxResponse: &sdktypes.TxResponse{TxHash: "ABCD"}},
+		getTxMethod:       status.Error(codes.Internal, "tx (ABCD) not found"),
+	})
+
+	_, err := testQuerier("cosmos-hub", SDKv44, conn).Broadcast(context.Background(), []byte("tx"), BroadcastOptions{
+		WaitForInclusion: true,
+		Timeout:          10 * time.Second,
+	})
+	require.Equal(t, KindInternal, ClassifyError(err).Kind)
+	require.Equal(t, 1, conn.called(getTxMethod))
+}
+
+func TestBroadcastNoTxResponse(t *testing.T) {
+	conn := newFakeConn(map[string]interface{}{
+		broadcastTxMethod: &sdktx.BroadcastTxResponse{},
+	})
+
+	_, err := testQuerier("cosmos-hub", SDKv44, conn).Broadcast(context.Background(), []byte("tx"), BroadcastOptions{})
+	require.Equal(t, KindUpstreamUnavailable, ClassifyError(err).Kind)
+}
+
+func TestTxNotFound(t *testing.T) {
+	// Nodes before v0.44 return the Tendermint RPC error as is.
+	rpcErr := queryError("tx", status.Error(codes.Unknown, "RPC error -32603 - Internal error: tx (ABCD) not found"))
+
+	require.True(t, sdkAdapters[SDKv42].TxNotFound(rpcErr))
+	require.False(t, sdkAdapters[SDKv44].TxNotFound(rpcErr))
+	require.False(t, sdkAdapters[SDKv42].TxNotFound(queryError("tx", status.Error(codes.Unknown, "account sequence mismatch"))))
+}
@@ -0,0 +1,231 @@
+package sdkservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultCacheTTLs holds how long the results of each endpoint are cached by
+// default. Module parameters only change through governance, while pools
+// change with every block.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"stakingParams":       5 * time.Minute,
+	"distributionParams":  5 * time.Minute,
+	"mintParams":          5 * time.Minute,
+	"budgetParams":        5 * time.Minute,
+	"liquidityParams":     5 * time.Minute,
+	"mintInflation":       time.Minute,
+	"mintAnnualProvision": time.Minute,
+	"mintEpochProvisions": time.Minute,
+	"emoneyInflation":     time.Minute,
+	"stakingPool":         10 * time.Second,
+	"liquidityPools":      10 * time.Second,
+	"osmoPools":           10 * time.Second,
+	"crescentPools":       10 * time.Second,
+}
+
+// cacheControlKey is the gRPC metadata key clients set to "no-cache" to
+// bypass the cache.
+const cacheControlKey = "cache-control"
+
+// cacheSweepInterval is the minimum amount of time between two removals of
+// the expired entries.
+const cacheSweepInterval = time.Minute
+
+type noCacheKey struct{}
+
+// WithoutCache returns a context which makes the service skip its cache.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	if v, _ := ctx.Value(noCacheKey{}).(bool); v {
+		return true
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get(cacheControlKey) {
+		if strings.Contains(strings.ToLower(v), "no-cache") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseCacheTTLs parses a comma-separated list of endpoint=duration pairs,
+// and returns DefaultCacheTTLs overridden by them. A zero duration disables
+// caching for the endpoint.
+func ParseCacheTTLs(s string) (map[string]time.Duration, error) {
+	ret := make(map[string]time.Duration, len(DefaultCacheTTLs))
+	for k, v := range DefaultCacheTTLs {
+		ret[k] = v
+	}
+
+	if s == "" {
+		return ret, nil
+	}
+
+	for _, kv := range strings.Split(s, ",") {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid cache ttl %q, expected endpoint=duration", kv)
+		}
+
+		d, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache ttl %q, %w", kv, err)
+		}
+
+		ret[strings.TrimSpace(parts[0])] = d
+	}
+
+	return ret, nil
+}
+
+// CacheStats holds the cache counters of an endpoint.
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// TTLCache caches endpoint results for a per-endpoint amount of time.
+type TTLCache struct {
+	ttls map[string]time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	stats   map[string]*CacheStats
+	swept   time.Time
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewTTLCache returns a TTLCache which keeps the results of each endpoint
+// for the duration held in ttls. Endpoints without a TTL are not cached.
+func NewTTLCache(ttls map[string]time.Duration) *TTLCache {
+	return &TTLCache{
+		ttls:    ttls,
+		entries: map[string]cacheEntry{},
+		stats:   map[string]*CacheStats{},
+		swept:   time.Now(),
+	}
+}
+
+// Do returns the cached result of endpoint for key, as built by cacheKey,
+// calling fetch and caching its result on misses.
+// Errors are never cached.
+func (c *TTLCache) Do(ctx context.Context, endpoint, key string, fetch func() (interface{}, error)) (interface{}, error) {
+	ttl := c.ttls[endpoint]
+	if ttl <= 0 || cacheBypassed(ctx) {
+		return fetch()
+	}
+
+	key = endpoint + "/" + key
+	now := time.Now()
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	hit := ok && now.Before(e.expires)
+	c.count(endpoint, hit)
+	c.mu.Unlock()
+
+	if hit {
+		return e.value, nil
+	}
+
+	v, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{
+		value:   v,
+		expires: now.Add(ttl),
+	}
+
+	if now.Sub(c.swept) >= cacheSweepInterval {
+		c.sweep(now)
+	}
+	c.mu.Unlock()
+
+	return v, nil
+}
+
+// sweep removes the entries expired at now, which are otherwise only
+// replaced when their key is requested again. c.mu must be held.
+func (c *TTLCache) sweep(now time.Time) {
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.swept = now
+}
+
+// Stats returns the hit and miss counters of each endpoint.
+func (c *TTLCache) Stats() map[string]CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ret := make(map[string]CacheStats, len(c.stats))
+	for k, v := range c.stats {
+		ret[k] = *v
+	}
+
+	return ret
+}
+
+func (c *TTLCache) count(endpoint string, hit bool) {
+	s, ok := c.stats[endpoint]
+	if !ok {
+		s = &CacheStats{}
+		c.stats[endpoint] = s
+	}
+
+	if hit {
+		s.Hits++
+	} else {
+		s.Misses++
+	}
+}
+
+// cacheKey returns the cache key of a call to chainName with args.
+func cacheKey(chainName string, args ...interface{}) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(chainName))
+
+	for _, a := range args {
+		switch v := a.(type) {
+		case *int:
+			if v == nil {
+				b.WriteString("/-")
+				continue
+			}
+
+			fmt.Fprintf(&b, "/%d", *v)
+		case *string:
+			if v == nil {
+				b.WriteString("/-")
+				continue
+			}
+
+			fmt.Fprintf(&b, "/%q", *v)
+		default:
+			fmt.Fprintf(&b, "/%v", v)
+		}
+	}
+
+	return b.String()
+}
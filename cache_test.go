@@ -0,0 +1,120 @@
+package sdkservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+// counter returns a fetch function counting its calls.
+func counter(calls *int) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		*calls++
+		return *calls, nil
+	}
+}
+
+func TestTTLCacheExpiry(t *testing.T) {
+	c := NewTTLCache(map[string]time.Duration{"stakingPool": 50 * time.Millisecond})
+	ctx := context.Background()
+
+	var calls int
+	v, err := c.Do(ctx, "stakingPool", "cosmos-hub", counter(&calls))
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+
+	v, err = c.Do(ctx, "stakingPool", "cosmos-hub", counter(&calls))
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+
+	// Keys are cached separately.
+	v, err = c.Do(ctx, "stakingPool", "osmosis", counter(&calls))
+	require.NoError(t, err)
+	require.Equal(t, 2, v)
+
+	time.Sleep(60 * time.Millisecond)
+
+	v, err = c.Do(ctx, "stakingPool", "cosmos-hub", counter(&calls))
+	require.NoError(t, err)
+	require.Equal(t, 3, v)
+
+	require.Equal(t, map[string]CacheStats{"stakingPool": {Hits: 1, Misses: 3}}, c.Stats())
+}
+
+func TestTTLCacheBypass(t *testing.T) {
+	c := NewTTLCache(map[string]time.Duration{"stakingParams": time.Minute})
+
+	var calls int
+	_, err := c.Do(context.Background(), "stakingParams", "cosmos-hub", counter(&calls))
+	require.NoError(t, err)
+
+	for name, ctx := range map[string]context.Context{
+		"context":  WithoutCache(context.Background()),
+		"metadata": metadata.NewIncomingContext(context.Background(), metadata.Pairs(cacheControlKey, "No-Cache")),
+	} {
+		before := calls
+
+		v, err := c.Do(ctx, "stakingParams", "cosmos-hub", counter(&calls))
+		require.NoError(t, err, name)
+		require.Equal(t, before+1, v, name)
+	}
+
+	// Endpoints without a TTL aren't cached.
+	_, err = c.Do(context.Background(), "uncached", "cosmos-hub", counter(&calls))
+	require.NoError(t, err)
+	_, err = c.Do(context.Background(), "uncached", "cosmos-hub", counter(&calls))
+	require.NoError(t, err)
+	require.Equal(t, 5, calls)
+
+	// Bypassed and uncached calls aren't counted.
+	require.Equal(t, map[string]CacheStats{"stakingParams": {Misses: 1}}, c.Stats())
+}
+
+func TestTTLCacheErrors(t *testing.T) {
+	c := NewTTLCache(map[string]time.Duration{"stakingParams": time.Minute})
+	ctx := context.Background()
+
+	_, err := c.Do(ctx, "stakingParams", "cosmos-hub", func() (interface{}, error) {
+		return nil, errors.New("upstream down")
+	})
+	require.Error(t, err)
+
+	var calls int
+	v, err := c.Do(ctx, "stakingParams", "cosmos-hub", counter(&calls))
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+	require.Equal(t, map[string]CacheStats{"stakingParams": {Misses: 2}}, c.Stats())
+}
+
+func TestTTLCacheSweep(t *testing.T) {
+	c := NewTTLCache(map[string]time.Duration{
+		"stakingPool":   time.Millisecond,
+		"stakingParams": time.Hour,
+	})
+	ctx := context.Background()
+
+	var calls int
+	for _, chain := range []string{"cosmos-hub", "osmosis"} {
+		_, err := c.Do(ctx, "stakingPool", chain, counter(&calls))
+		require.NoError(t, err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	c.mu.Lock()
+	c.swept = time.Now().Add(-cacheSweepInterval)
+	c.mu.Unlock()
+
+	_, err := c.Do(ctx, "stakingParams", "cosmos-hub", counter(&calls))
+	require.NoError(t, err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	require.Len(t, c.entries, 1)
+	require.Contains(t, c.entries, "stakingParams/cosmos-hub")
+}
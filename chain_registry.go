@@ -0,0 +1,286 @@
+package sdkservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v2"
+)
+
+// ErrChainNotConfigured is returned when a request targets a chain which
+// isn't present in the chain registry.
+var ErrChainNotConfigured = errors.New("chain not configured")
+
+// Chain holds the endpoints and metadata of a chain the service can query.
+type Chain struct {
+	// Name is the name clients use to refer to the chain.
+	Name string `yaml:"name" json:"name"`
+
+	// ChainID is the network nodes must report, not checked if empty.
+	ChainID string `yaml:"chain_id" json:"chain_id"`
+
+	// AppVersion, if set, must be a prefix of the application version
+	// reported by nodes.
+	AppVersion string `yaml:"app_version" json:"app_version"`
+
+	// SDKVersion is the cosmos-sdk generation run by the chain, either v42
+	// or v44. It's detected from the nodes if empty.
+	SDKVersion string `yaml:"sdk_version" json:"sdk_version"`
+
+	// CodecExtensions are the additional types the chain uses, like
+	// ethermint for Ethermint based chains.
+	CodecExtensions []string `yaml:"codec_extensions" json:"codec_extensions"`
+
+	// GRPC is the list of gRPC endpoints, in host[:port] form.
+	GRPC []string `yaml:"grpc" json:"grpc"`
+
+	// REST is the URL of the REST/LCD server.
+	REST string `yaml:"rest" json:"rest"`
+
+	// RPC is the URL of the Tendermint RPC server.
+	RPC string `yaml:"rpc" json:"rpc"`
+
+	// Bech32Prefix is the account address prefix of the chain, used when
+	// requests give hex addresses without a prefix.
+	Bech32Prefix string   `yaml:"bech32_prefix" json:"bech32_prefix"`
+	FeeDenoms    []string `yaml:"fee_denoms" json:"fee_denoms"`
+
+	TLS  TLSConfig  `yaml:"tls" json:"tls"`
+	Auth AuthConfig `yaml:"auth" json:"auth"`
+
+	// Balancing is the node selection policy, either round_robin (default)
+	// or least_latency.
+	Balancing string `yaml:"balancing" json:"balancing"`
+
+	// EjectionTime is how long a failing node is kept out of rotation.
+	EjectionTime Duration `yaml:"ejection_time" json:"ejection_time"`
+
+	Health HealthConfig `yaml:"health" json:"health"`
+
+	transport transport
+}
+
+// GRPCTarget returns the dial target for the idx-th gRPC endpoint of c.
+// If the endpoint doesn't specify a port, port is used instead, or the default
+// gRPC port if port is nil.
+func (c Chain) GRPCTarget(idx int, port *int) string {
+	endpoint := c.GRPC[idx]
+	if _, _, err := net.SplitHostPort(endpoint); err == nil {
+		return endpoint
+	}
+
+	if port == nil {
+		port = &grpcPort
+	}
+
+	return net.JoinHostPort(endpoint, strconv.Itoa(*port))
+}
+
+// DialOptions returns the gRPC dial options needed to connect to the nodes of c.
+func (c Chain) DialOptions() []grpc.DialOption {
+	return c.transport.dialOptions()
+}
+
+// TransportKey identifies the transport settings of c, chains with the same
+// key can share connections.
+func (c Chain) TransportKey() string {
+	return c.transport.key
+}
+
+// validBech32Prefix reports whether addresses with prefix can be decoded,
+// encoding doesn't check the characters of prefixes.
+func validBech32Prefix(prefix string) bool {
+	addr, err := bech32.ConvertAndEncode(prefix, make([]byte, 20))
+	if err != nil {
+		return false
+	}
+
+	hrp, _, err := bech32.DecodeAndConvert(addr)
+
+	return err == nil && hrp == prefix
+}
+
+func (c Chain) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("missing chain name")
+	}
+
+	if len(c.GRPC) == 0 {
+		return fmt.Errorf("chain %s: no gRPC endpoints", c.Name)
+	}
+
+	for _, e := range c.GRPC {
+		if e == "" {
+			return fmt.Errorf("chain %s: empty gRPC endpoint", c.Name)
+		}
+	}
+
+	if _, ok := sdkAdapters[c.SDKVersion]; c.SDKVersion != "" && !ok {
+		return fmt.Errorf("chain %s: unsupported sdk version %s", c.Name, c.SDKVersion)
+	}
+
+	if c.Bech32Prefix != "" && !validBech32Prefix(c.Bech32Prefix) {
+		return fmt.Errorf("chain %s: invalid bech32 prefix %q", c.Name, c.Bech32Prefix)
+	}
+
+	for _, e := range c.CodecExtensions {
+		if _, ok := codecExtensions[e]; !ok {
+			return fmt.Errorf("chain %s: unknown codec extension %s", c.Name, e)
+		}
+	}
+
+	switch c.Balancing {
+	case "", RoundRobin, LeastLatency:
+	default:
+		return fmt.Errorf("chain %s: unknown balancing policy %s", c.Name, c.Balancing)
+	}
+
+	if c.EjectionTime < 0 {
+		return fmt.Errorf("chain %s: negative ejection time", c.Name)
+	}
+
+	if c.Health.MaxHeightLag < 0 || c.Health.MaxBlockAge < 0 {
+		return fmt.Errorf("chain %s: negative health thresholds", c.Name)
+	}
+
+	return nil
+}
+
+func (c Chain) ejectionTime() time.Duration {
+	if c.EjectionTime == 0 {
+		return DefaultEjectionTime
+	}
+
+	return time.Duration(c.EjectionTime)
+}
+
+// ChainRegistry maps chain names to their configuration.
+type ChainRegistry struct {
+	chains map[string]Chain
+}
+
+type chainRegistryFile struct {
+	Chains []Chain `yaml:"chains" json:"chains"`
+}
+
+// NewChainRegistry returns a ChainRegistry holding chains.
+// Chain names are case-insensitive and must be unique.
+func NewChainRegistry(chains []Chain) (*ChainRegistry, error) {
+	r := &ChainRegistry{
+		chains: make(map[string]Chain, len(chains)),
+	}
+
+	for _, c := range chains {
+		if err := c.validate(); err != nil {
+			return nil, err
+		}
+
+		t, err := newTransport(c.TLS, c.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("chain %s: %w", c.Name, err)
+		}
+
+		c.transport = t
+
+		key := strings.ToLower(c.Name)
+		if _, ok := r.chains[key]; ok {
+			return nil, fmt.Errorf("chain %s is configured more than once", c.Name)
+		}
+
+		r.chains[key] = c
+	}
+
+	return r, nil
+}
+
+// LoadChainRegistry reads a ChainRegistry from the YAML or JSON file at path.
+// Files with a .json extension are decoded as JSON, all the others as YAML.
+func LoadChainRegistry(path string) (*ChainRegistry, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read chain registry, %w", err)
+	}
+
+	var f chainRegistryFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &f)
+	} else {
+		err = yaml.UnmarshalStrict(data, &f)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode chain registry %s, %w", path, err)
+	}
+
+	return NewChainRegistry(f.Chains)
+}
+
+// Chain returns the configuration of the chain called name.
+func (r *ChainRegistry) Chain(name string) (Chain, error) {
+	c, ok := r.chains[strings.ToLower(name)]
+	if !ok {
+		return Chain{}, fmt.Errorf("%w: %s", ErrChainNotConfigured, name)
+	}
+
+	return c, nil
+}
+
+// Chains returns the configuration of all the chains in the registry,
+// sorted by name.
+func (r *ChainRegistry) Chains() []Chain {
+	ret := make([]Chain, 0, len(r.chains))
+	for _, c := range r.chains {
+		ret = append(ret, c)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+
+	return ret
+}
+
+// Duration is a time.Duration which can be decoded from strings such as "30s"
+// in both YAML and JSON.
+type Duration time.Duration
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	return d.parse(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return d.parse(s)
+}
+
+func (d *Duration) parse(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
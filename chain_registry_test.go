@@ -0,0 +1,83 @@
+package sdkservice
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadChainRegistry(t *testing.T) {
+	yamlPath := writeTestFile(t, "chains.yaml", []byte(`
+chains:
+  - name: Cosmos-Hub
+    chain_id: cosmoshub-4
+    sdk_version: v44
+    grpc:
+      - cosmos-hub:9090
+      - cosmos-hub-backup
+    balancing: least_latency
+    ejection_time: 1m
+  - name: osmosis
+    grpc:
+      - osmosis
+`))
+
+	jsonPath := writeTestFile(t, "chains.json", []byte(`{"chains": [
+		{"name": "Cosmos-Hub", "chain_id": "cosmoshub-4", "sdk_version": "v44",
+		 "grpc": ["cosmos-hub:9090", "cosmos-hub-backup"], "balancing": "least_latency", "ejection_time": "1m"},
+		{"name": "osmosis", "grpc": ["osmosis"]}
+	]}`))
+
+	for _, path := range []string{yamlPath, jsonPath} {
+		r, err := LoadChainRegistry(path)
+		require.NoError(t, err, path)
+
+		// Names are looked up regardless of their case.
+		c, err := r.Chain("cosmos-hub")
+		require.NoError(t, err, path)
+		require.Equal(t, "Cosmos-Hub", c.Name, path)
+		require.Equal(t, "cosmoshub-4", c.ChainID, path)
+		require.Equal(t, LeastLatency, c.Balancing, path)
+		require.Equal(t, time.Minute, c.ejectionTime(), path)
+
+		port := 9091
+		require.Equal(t, "cosmos-hub:9090", c.GRPCTarget(0, &port), path)
+		require.Equal(t, "cosmos-hub-backup:9091", c.GRPCTarget(1, &port), path)
+		require.Equal(t, "cosmos-hub-backup:9090", c.GRPCTarget(1, nil), path)
+
+		osmosis, err := r.Chain("OSMOSIS")
+		require.NoError(t, err, path)
+		require.Equal(t, DefaultEjectionTime, osmosis.ejectionTime(), path)
+
+		var names []string
+		for _, c := range r.Chains() {
+			names = append(names, c.Name)
+		}
+		require.Equal(t, []string{"Cosmos-Hub", "osmosis"}, names, path)
+
+		_, err = r.Chain("juno")
+		require.True(t, errors.Is(err, ErrChainNotConfigured), path)
+	}
+}
+
+func TestLoadChainRegistryInvalid(t *testing.T) {
+	for name, file := range map[string]string{
+		"unknown field":     "chains:\n  - name: cosmos-hub\n    grpc: [cosmos-hub]\n    grcp: [typo]\n",
+		"invalid duration":  "chains:\n  - name: cosmos-hub\n    grpc: [cosmos-hub]\n    ejection_time: soon\n",
+		"duplicate name":    "chains:\n  - name: cosmos-hub\n    grpc: [a]\n  - name: Cosmos-Hub\n    grpc: [b]\n",
+		"missing name":      "chains:\n  - grpc: [cosmos-hub]\n",
+		"missing endpoints": "chains:\n  - name: cosmos-hub\n",
+		"empty endpoint":    "chains:\n  - name: cosmos-hub\n    grpc: ['']\n",
+		"sdk version":       "chains:\n  - name: cosmos-hub\n    grpc: [cosmos-hub]\n    sdk_version: v46\n",
+		"balancing":         "chains:\n  - name: cosmos-hub\n    grpc: [cosmos-hub]\n    balancing: random\n",
+		"codec extension":   "chains:\n  - name: cosmos-hub\n    grpc: [cosmos-hub]\n    codec_extensions: [unknown]\n",
+	} {
+		_, err := LoadChainRegistry(writeTestFile(t, "chains.yaml", []byte(file)))
+		require.Error(t, err, name)
+	}
+
+	_, err := LoadChainRegistry("/nonexistent/chains.yaml")
+	require.Error(t, err)
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	sdkutilitiesapi "github.com/emerishq/sdk-service"
+	log "github.com/emerishq/sdk-service-meta/gen/log"
+	goahttp "goa.design/goa/v3/http"
+)
+
+// extraHandler is an HTTP endpoint served next to the ones generated from
+// the service design.
+type extraHandler struct {
+	verb    string
+	pattern string
+	handler func(sdkutilitiesapi.Service, goahttp.Muxer) http.HandlerFunc
+}
+
+// maxPassthroughRequestSize is the maximum size of passthrough requests.
+const maxPassthroughRequestSize = 1 << 20
+
+var extraHandlers = []extraHandler{
+	{"GET", "/nodes/health", nodesHealthHandler},
+	{"GET", "/cache/stats", cacheStatsHandler},
+	{"POST", "/chain/{chainName}/query", passthroughHandler},
+	{"POST", "/chain/{chainName}/tx/summary", txSummaryHandler},
+	{"POST", "/chain/{chainName}/broadcast", broadcastHandler},
+	{"GET", "/chain/{chainName}/tx/{hash}/status", txStatusHandler},
+	{"GET", "/chain/{chainName}/txs", searchTxsHandler},
+	{"GET", "/chain/{chainName}/account/{address}/txs", accountTxsHandler},
+	{"GET", "/chain/{chainName}/balances", balancesHandler},
+	{"GET", "/chain/{chainName}/account", accountDetailsHandler},
+	{"GET", "/address/{address}", convertAddressHandler},
+}
+
+// mountExtraHandlers mounts extraHandlers on mux.
+func mountExtraHandlers(mux goahttp.Muxer, svc sdkutilitiesapi.Service, logger *log.Logger) {
+	for _, h := range extraHandlers {
+		mux.Handle(h.verb, h.pattern, h.handler(svc, mux))
+		logger.Infof("HTTP mounted on %s %s", h.verb, h.pattern)
+	}
+}
+
+func nodesHealthHandler(svc sdkutilitiesapi.Service, _ goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, svc.NodesHealth())
+	}
+}
+
+func cacheStatsHandler(svc sdkutilitiesapi.Service, _ goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, svc.CacheStats())
+	}
+}
+
+// passthroughHandler invokes the gRPC method given in the method query
+// parameter, with the request body as the proto-JSON encoded request.
+func passthroughHandler(svc sdkutilitiesapi.Service, mux goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		request, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPassthroughRequestSize))
+		if err != nil {
+			writeError(w, &sdkutilitiesapi.Error{
+				Kind: sdkutilitiesapi.KindInvalidArgument,
+				Err:  fmt.Errorf("cannot read request, %w", err),
+			})
+			return
+		}
+
+		res, err := svc.Passthrough(r.Context(), mux.Vars(r)["chainName"], r.URL.Query().Get("method"), request)
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(res)
+	}
+}
+
+// txSummaryRequest is the body of tx summary requests.
+type txSummaryRequest struct {
+	TxBytes []byte `json:"tx_bytes"`
+}
+
+// txSummaryHandler decodes the base64 encoded transaction in the body.
+func txSummaryHandler(svc sdkutilitiesapi.Service, mux goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req txSummaryRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPassthroughRequestSize)).Decode(&req); err != nil {
+			writeError(w, &sdkutilitiesapi.Error{
+				Kind: sdkutilitiesapi.KindInvalidArgument,
+				Err:  fmt.Errorf("cannot decode request, %w", err),
+			})
+			return
+		}
+
+		res, err := svc.TxSummary(r.Context(), mux.Vars(r)["chainName"], req.TxBytes)
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// broadcastRequest is the body of broadcast requests, Timeout is a duration
+// like 45s.
+type broadcastRequest struct {
+	TxBytes          []byte `json:"tx_bytes"`
+	Mode             string `json:"mode"`
+	WaitForInclusion bool   `json:"wait_for_inclusion"`
+	Timeout          string `json:"timeout"`
+}
+
+// broadcastHandler broadcasts the base64 encoded transaction in the body
+// with the requested mode, returning the transaction response even when it
+// failed.
+func broadcastHandler(svc sdkutilitiesapi.Service, mux goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req broadcastRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPassthroughRequestSize)).Decode(&req); err != nil {
+			writeError(w, &sdkutilitiesapi.Error{
+				Kind: sdkutilitiesapi.KindInvalidArgument,
+				Err:  fmt.Errorf("cannot decode request, %w", err),
+			})
+			return
+		}
+
+		opts := sdkutilitiesapi.BroadcastOptions{
+			Mode:             req.Mode,
+			WaitForInclusion: req.WaitForInclusion,
+		}
+
+		if req.Timeout != "" {
+			timeout, err := time.ParseDuration(req.Timeout)
+			if err != nil {
+				writeError(w, &sdkutilitiesapi.Error{
+					Kind: sdkutilitiesapi.KindInvalidArgument,
+					Err:  fmt.Errorf("invalid timeout, %w", err),
+				})
+				return
+			}
+
+			opts.Timeout = timeout
+		}
+
+		res, err := svc.Broadcast(r.Context(), mux.Vars(r)["chainName"], req.TxBytes, opts)
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		// Failed transactions are returned with the status code of their
+		// error, and the result as body.
+		status := http.StatusOK
+		if err := res.Err(); err != nil {
+			status = sdkutilitiesapi.ClassifyError(err).StatusCode()
+		}
+
+		writeJSON(w, status, res)
+	}
+}
+
+func txStatusHandler(svc sdkutilitiesapi.Service, mux goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		res, err := svc.TxStatus(r.Context(), vars["chainName"], vars["hash"])
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// searchTxsHandler searches transactions with the conditions given as query
+// parameters: event (repeated), sender, recipient, action, min_height and
+// max_height, paginated with page, limit and order.
+func searchTxsHandler(svc sdkutilitiesapi.Service, mux goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		search, err := parseTxSearch(query)
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		search.Events = query["event"]
+		search.Sender = query.Get("sender")
+		search.Recipient = query.Get("recipient")
+		search.Action = query.Get("action")
+
+		res, err := svc.SearchTxs(r.Context(), mux.Vars(r)["chainName"], search)
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// accountTxsHandler returns the transactions sent by an address, or received
+// by it when the role query parameter is recipient.
+func accountTxsHandler(svc sdkutilitiesapi.Service, mux goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		query := r.URL.Query()
+
+		search, err := parseTxSearch(query)
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		switch query.Get("role") {
+		case "", "sender":
+			search.Sender = vars["address"]
+		case "recipient":
+			search.Recipient = vars["address"]
+		default:
+			writeError(w, &sdkutilitiesapi.Error{
+				Kind: sdkutilitiesapi.KindInvalidArgument,
+				Err:  fmt.Errorf("unknown role %q, must be sender or recipient", query.Get("role")),
+			})
+			return
+		}
+
+		res, err := svc.SearchTxs(r.Context(), vars["chainName"], search)
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// balancesHandler returns the balances of the account given either by the
+// address query parameter, or by address_hex and bech32_prefix.
+func balancesHandler(svc sdkutilitiesapi.Service, mux goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := svc.Balances(r.Context(), mux.Vars(r)["chainName"], accountAddress(r.URL.Query()))
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// accountDetailsHandler returns the details of the account given like for
+// balancesHandler.
+func accountDetailsHandler(svc sdkutilitiesapi.Service, mux goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := svc.AccountDetails(r.Context(), mux.Vars(r)["chainName"], accountAddress(r.URL.Query()))
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// convertAddressHandler converts the hex or bech32 address of the path to
+// bech32 with the optional prefix query parameter, and to the addresses of
+// the configured chains.
+func convertAddressHandler(svc sdkutilitiesapi.Service, mux goahttp.Muxer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := svc.ConvertAddress(mux.Vars(r)["address"], r.URL.Query().Get("prefix"))
+		if err != nil {
+			writeError(w, sdkutilitiesapi.ClassifyError(err))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// accountAddress returns the account given either by the address query
+// parameter, or by address_hex and bech32_prefix.
+func accountAddress(query url.Values) sdkutilitiesapi.AccountAddress {
+	return sdkutilitiesapi.AccountAddress{
+		Bech32:       query.Get("address"),
+		Hex:          query.Get("address_hex"),
+		Bech32Prefix: query.Get("bech32_prefix"),
+	}
+}
+
+// parseTxSearch returns the height range, pagination and ordering of a
+// transaction search from query.
+func parseTxSearch(query url.Values) (sdkutilitiesapi.TxSearch, error) {
+	search := sdkutilitiesapi.TxSearch{
+		Order: query.Get("order"),
+	}
+
+	for _, p := range []struct {
+		name  string
+		value *int64
+	}{
+		{"min_height", &search.MinHeight},
+		{"max_height", &search.MaxHeight},
+	} {
+		if v := query.Get(p.name); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return search, &sdkutilitiesapi.Error{
+					Kind: sdkutilitiesapi.KindInvalidArgument,
+					Err:  fmt.Errorf("invalid %s, %w", p.name, err),
+				}
+			}
+
+			*p.value = n
+		}
+	}
+
+	for _, p := range []struct {
+		name  string
+		value *uint64
+	}{
+		{"page", &search.Page},
+		{"limit", &search.Limit},
+	} {
+		if v := query.Get(p.name); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return search, &sdkutilitiesapi.Error{
+					Kind: sdkutilitiesapi.KindInvalidArgument,
+					Err:  fmt.Errorf("invalid %s, %w", p.name, err),
+				}
+			}
+
+			*p.value = n
+		}
+	}
+
+	return search, nil
+}
+
+func writeError(w http.ResponseWriter, err *sdkutilitiesapi.Error) {
+	writeJSON(w, err.StatusCode(), err)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -5,9 +5,11 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
+	sdkutilitiesapi "github.com/emerishq/sdk-service"
 	sdkutilitiessvr "github.com/emerishq/sdk-service-meta/gen/http/sdk_utilities/server"
 	log "github.com/emerishq/sdk-service-meta/gen/log"
 	sdkutilities "github.com/emerishq/sdk-service-meta/gen/sdk_utilities"
@@ -19,7 +21,7 @@ import (
 
 // handleHTTPServer starts configures and starts a HTTP server on the given
 // URL. It shuts down the server if any error is received in the error channel.
-func handleHTTPServer(ctx context.Context, u *url.URL, sdkUtilitiesEndpoints *sdkutilities.Endpoints, wg *sync.WaitGroup, errc chan error, logger *log.Logger, debug bool) {
+func handleHTTPServer(ctx context.Context, u *url.URL, sdkUtilitiesSvc sdkutilitiesapi.Service, sdkUtilitiesEndpoints *sdkutilities.Endpoints, wg *sync.WaitGroup, errc chan error, logger *log.Logger, debug bool) {
 
 	// Setup goa log adapter.
 	var (
@@ -54,7 +56,7 @@ func handleHTTPServer(ctx context.Context, u *url.URL, sdkUtilitiesEndpoints *sd
 	)
 	{
 		eh := errorHandler(logger)
-		sdkUtilitiesServer = sdkutilitiessvr.New(nil, mux, dec, enc, eh, nil, nil)
+		sdkUtilitiesServer = sdkutilitiessvr.New(nil, mux, dec, enc, eh, errorFormatter, nil)
 		if debug {
 			servers := goahttp.Servers{
 				sdkUtilitiesServer,
@@ -64,11 +66,13 @@ func handleHTTPServer(ctx context.Context, u *url.URL, sdkUtilitiesEndpoints *sd
 	}
 	// Configure the mux.
 	sdkutilitiessvr.Mount(mux, sdkUtilitiesServer)
+	mountExtraHandlers(mux, sdkUtilitiesSvc, logger)
 
 	// Wrap the multiplexer with additional middlewares. Middlewares mounted
 	// here apply to all the service endpoints.
 	var handler http.Handler = mux
 	{
+		handler = withoutCache(handler)
 		handler = httpmdlwr.Log(adapter)(handler)
 		handler = httpmdlwr.RequestID()(handler)
 	}
@@ -101,6 +105,26 @@ func handleHTTPServer(ctx context.Context, u *url.URL, sdkUtilitiesEndpoints *sd
 	}()
 }
 
+// withoutCache makes the service skip its cache for requests with a
+// Cache-Control: no-cache header.
+func withoutCache(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, v := range r.Header.Values("Cache-Control") {
+			if strings.Contains(strings.ToLower(v), "no-cache") {
+				r = r.WithContext(sdkutilitiesapi.WithoutCache(r.Context()))
+				break
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// errorFormatter encodes errors with the HTTP status code matching their kind.
+func errorFormatter(err error) goahttp.Statuser {
+	return sdkutilitiesapi.ClassifyError(err)
+}
+
 // errorHandler returns a function that writes and logs the given error.
 // The function also writes and logs the error unique ID so that it's possible
 // to correlate.
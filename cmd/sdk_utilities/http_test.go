@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdkutilitiesapi "github.com/emerishq/sdk-service"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithoutCache(t *testing.T) {
+	cache := sdkutilitiesapi.NewTTLCache(map[string]time.Duration{"stakingParams": time.Minute})
+
+	var calls int
+	handler := withoutCache(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = cache.Do(r.Context(), "stakingParams", "cosmos-hub", func() (interface{}, error) {
+			calls++
+			return calls, nil
+		})
+	}))
+
+	for _, cacheControl := range []string{"", "", "no-cache", "max-age=0, No-Cache", "max-age=60"} {
+		r := httptest.NewRequest(http.MethodGet, "/staking_params", nil).WithContext(context.Background())
+		if cacheControl != "" {
+			r.Header.Set("Cache-Control", cacheControl)
+		}
+
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	require.Equal(t, 3, calls)
+}
+
+func TestErrorFormatter(t *testing.T) {
+	for _, tt := range []struct {
+		err    error
+		status int
+		kind   sdkutilitiesapi.ErrorKind
+	}{
+		{fmt.Errorf("chain test: %w", status.Error(codes.NotFound, "account not found")), http.StatusNotFound, sdkutilitiesapi.KindNotFound},
+		{fmt.Errorf("cannot query bank, %w", status.Error(codes.Unavailable, "connection refused")), http.StatusBadGateway, sdkutilitiesapi.KindUpstreamUnavailable},
+		{fmt.Errorf("cannot query bank, %w", context.DeadlineExceeded), http.StatusGatewayTimeout, sdkutilitiesapi.KindUpstreamTimeout},
+		{fmt.Errorf("chain osmosis: %w", sdkutilitiesapi.ErrChainNotConfigured), http.StatusNotFound, sdkutilitiesapi.KindUnsupportedChain},
+		{fmt.Errorf("chain test: %w", sdkutilitiesapi.ErrChainMismatch), http.StatusMisdirectedRequest, sdkutilitiesapi.KindChainMismatch},
+		{errors.New("cannot unmarshal response"), http.StatusInternalServerError, sdkutilitiesapi.KindInternal},
+	} {
+		st := errorFormatter(tt.err)
+		require.Equal(t, tt.status, st.StatusCode(), tt.err.Error())
+
+		body, err := json.Marshal(st)
+		require.NoError(t, err)
+
+		var res struct {
+			Kind    sdkutilitiesapi.ErrorKind `json:"kind"`
+			Message string                    `json:"message"`
+		}
+		require.NoError(t, json.Unmarshal(body, &res))
+		require.Equal(t, tt.kind, res.Kind, tt.err.Error())
+		require.Equal(t, tt.err.Error(), res.Message)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 
@@ -17,15 +18,10 @@ import (
 )
 
 var (
-	SupportedSDKVersion = ""
-	Version             = "not specified"
+	Version = "not specified"
 )
 
 func main() {
-	if SupportedSDKVersion == "" {
-		panic("missing sdk version at compile time, panic!")
-	}
-
 	// Define command line flags, add any other flag required to configure the
 	// service.
 	var (
@@ -35,6 +31,19 @@ func main() {
 		grpcPortF = flag.String("grpc-port", "", "gRPC port (overrides host gRPC port specified in service design)")
 		secureF   = flag.Bool("secure", false, "Use secure scheme (https or grpcs)")
 		dbgF      = flag.Bool("debug", false, "Log request and response bodies")
+		chainsF   = flag.String("chains", "", "Path to the YAML or JSON chain registry file")
+		idleF     = flag.Duration("upstream-idle-timeout", sdkutilitiesapi.DefaultIdleTimeout, "Close upstream gRPC connections unused for longer than this (0 disables)")
+		healthF   = flag.Duration("health-check-interval", sdkutilitiesapi.DefaultHealthCheckInterval, "Interval between upstream node health checks (0 disables)")
+		blobMemF  = flag.Int64("immutable-cache-size", sdkutilitiesapi.DefaultImmutableCacheSize, "Memory, in bytes, used to cache finalized blocks and transactions (0 disables)")
+		blobPathF = flag.String("immutable-cache-path", "", "Path of the on-disk finalized blocks and transactions cache (disabled if empty)")
+		blobDiskF = flag.Int64("immutable-cache-disk-size", sdkutilitiesapi.DefaultImmutableCacheDiskSize, "Size, in bytes, of the on-disk finalized blocks and transactions cache")
+		cacheF    = flag.String("cache-ttl", "", "Comma-separated endpoint=duration cache TTL overrides, e.g. stakingParams=10m,stakingPool=5s (0 disables)")
+		trackF    = flag.Bool("track-txs", false, "Track broadcast transactions until they're included in a block, fail or expire")
+		trackIntF = flag.Duration("track-poll-interval", sdkutilitiesapi.DefaultTrackerPollInterval, "Interval between lookups of tracked pending transactions")
+		trackExpF = flag.Duration("track-expiry", sdkutilitiesapi.DefaultTrackerExpiry, "Amount of time after which tracked pending transactions expire")
+		trackRetF = flag.Duration("track-retention", sdkutilitiesapi.DefaultTrackerRetention, "Amount of time the status of finished transactions is kept")
+		hookURLF  = flag.String("tx-webhook-url", "", "URL notified of tracked transactions status changes, signed with the TX_WEBHOOK_SECRET environment variable, which must be set (disabled if empty)")
+		passF     = flag.String("passthrough-services", strings.Join(sdkutilitiesapi.DefaultPassthroughServices, ","), "Comma-separated gRPC services which can be queried through the passthrough endpoint, e.g. cosmos.gov.v1beta1.Query")
 	)
 	flag.Parse()
 
@@ -46,12 +55,62 @@ func main() {
 		logger = log.New("sdkutilitiesapi", false)
 	}
 
+	// Load the chain registry.
+	var (
+		chains *sdkutilitiesapi.ChainRegistry
+	)
+	{
+		if *chainsF == "" {
+			fmt.Fprintln(os.Stderr, "missing chain registry, set it with -chains")
+			os.Exit(1)
+		}
+
+		var err error
+		chains, err = sdkutilitiesapi.LoadChainRegistry(*chainsF)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+
+		for _, c := range chains.Chains() {
+			logger.Infow("chain configured", "name", c.Name, "grpc", c.GRPC)
+		}
+	}
+
 	// Initialize the services.
 	var (
-		sdkUtilitiesSvc sdkutilities.Service
+		sdkUtilitiesSvc sdkutilitiesapi.Service
 	)
 	{
-		sdkUtilitiesSvc = sdkutilitiesapi.NewSdkUtilities(logger, *dbgF)
+		cacheTTLs, err := sdkutilitiesapi.ParseCacheTTLs(*cacheF)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+
+		sdkUtilitiesSvc, err = sdkutilitiesapi.NewSdkUtilities(logger, *dbgF, chains, sdkutilitiesapi.Options{
+			IdleTimeout:         *idleF,
+			HealthCheckInterval: *healthF,
+			CacheTTLs:           cacheTTLs,
+			PassthroughServices: splitList(*passF),
+			Tracker: sdkutilitiesapi.TrackerOptions{
+				Enabled:       *trackF,
+				PollInterval:  *trackIntF,
+				Expiry:        *trackExpF,
+				Retention:     *trackRetF,
+				WebhookURL:    *hookURLF,
+				WebhookSecret: os.Getenv("TX_WEBHOOK_SECRET"),
+			},
+			ImmutableCache: sdkutilitiesapi.ImmutableCacheOptions{
+				MaxMemoryBytes: *blobMemF,
+				Path:           *blobPathF,
+				MaxDiskBytes:   *blobDiskF,
+			},
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
 	}
 
 	// Wrap the services in endpoints that can be invoked from other services
@@ -61,9 +120,10 @@ func main() {
 	)
 	{
 		sdkUtilitiesEndpoints = sdkutilities.NewEndpoints(sdkUtilitiesSvc)
+		sdkUtilitiesEndpoints.Use(sdkutilitiesapi.ClassifyErrors)
 	}
 
-	logger.Infow("starting sdk-service", "version", Version, "supported_sdk_version", SupportedSDKVersion)
+	logger.Infow("starting sdk-service", "version", Version, "supported_sdk_versions", sdkutilitiesapi.SupportedSDKVersions())
 
 	// Create channel used by both the signal handler and server goroutines
 	// to notify the main goroutine when to stop the server.
@@ -106,7 +166,7 @@ func main() {
 			} else if u.Port() == "" {
 				u.Host = net.JoinHostPort(u.Host, "80")
 			}
-			handleHTTPServer(ctx, u, sdkUtilitiesEndpoints, &wg, errc, logger, *dbgF)
+			handleHTTPServer(ctx, u, sdkUtilitiesSvc, sdkUtilitiesEndpoints, &wg, errc, logger, *dbgF)
 		}
 
 		{
@@ -158,7 +218,7 @@ func main() {
 			} else if u.Port() == "" {
 				u.Host = net.JoinHostPort(u.Host, "80")
 			}
-			handleHTTPServer(ctx, u, sdkUtilitiesEndpoints, &wg, errc, logger, *dbgF)
+			handleHTTPServer(ctx, u, sdkUtilitiesSvc, sdkUtilitiesEndpoints, &wg, errc, logger, *dbgF)
 		}
 
 		{
@@ -198,5 +258,22 @@ func main() {
 	cancel()
 
 	wg.Wait()
+
+	if err := sdkUtilitiesSvc.Close(); err != nil {
+		logger.Errorw("cannot close upstream connections", "error", err)
+	}
+
 	logger.Info("exited")
 }
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	ret := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			ret = append(ret, item)
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,133 @@
+package sdkservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	encodingproto "google.golang.org/grpc/encoding/proto"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// flightGroup coalesces concurrent identical upstream calls, so that they
+// share a single in-flight request.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is an in-flight upstream call, whose marshaled reply or error is
+// handed to all of its callers once done is closed.
+type flight struct {
+	done  chan struct{}
+	reply []byte
+	err   error
+}
+
+// invokeFunc sends a call to the upstream, storing its response in reply.
+type invokeFunc func(ctx context.Context, reply interface{}) error
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{
+		calls: map[string]*flight{},
+	}
+}
+
+// do calls invoke, unless an identical call is already in flight, in which
+// case it waits for its outcome instead. Either way, the response is stored
+// in reply.
+// The shared call isn't canceled when one of its callers gives up, it runs
+// until the deadline of the caller which started it.
+func (g *flightGroup) do(ctx context.Context, key string, reply interface{}, invoke invokeFunc) error {
+	g.mu.Lock()
+	f, ok := g.calls[key]
+	if !ok {
+		f = &flight{
+			done: make(chan struct{}),
+		}
+		g.calls[key] = f
+
+		callCtx, cancel := detachContext(ctx)
+		go g.run(callCtx, cancel, key, f, reflect.TypeOf(reply).Elem(), invoke)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return contextError(ctx.Err())
+	case <-f.done:
+	}
+
+	if f.err != nil {
+		return f.err
+	}
+
+	return protoCodec().Unmarshal(f.reply, reply)
+}
+
+func (g *flightGroup) run(ctx context.Context, cancel context.CancelFunc, key string, f *flight, replyType reflect.Type, invoke invokeFunc) {
+	defer cancel()
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		close(f.done)
+	}()
+
+	reply := reflect.New(replyType).Interface()
+	if f.err = invoke(ctx, reply); f.err != nil {
+		return
+	}
+
+	f.reply, f.err = protoCodec().Marshal(reply)
+}
+
+// flightKey returns the key identifying calls to method of chainName with
+// args, at the block height requested in ctx if any.
+func flightKey(ctx context.Context, chainName, method string, args interface{}) (string, error) {
+	argsBytes, err := protoCodec().Marshal(args)
+	if err != nil {
+		return "", err
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	return strings.Join([]string{
+		chainName,
+		method,
+		strings.Join(md.Get(grpctypes.GRPCBlockHeightHeader), ","),
+		string(argsBytes),
+	}, "\x00"), nil
+}
+
+// detachContext returns a context which carries the outgoing metadata and
+// deadline of ctx, without being canceled along with it.
+func detachContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	ret := metadata.NewOutgoingContext(context.Background(), md)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(ret, deadline)
+	}
+
+	return context.WithCancel(ret)
+}
+
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	return status.Error(codes.Canceled, err.Error())
+}
+
+func protoCodec() encoding.Codec {
+	return encoding.GetCodec(encodingproto.Name)
+}
@@ -0,0 +1,144 @@
+package sdkservice
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// blockingInvoke returns an invokeFunc answering with a balance once
+// release is closed, signaling started when first called and counting its
+// calls.
+func blockingInvoke(calls *int32, started chan<- struct{}, release <-chan struct{}, ctxErr *error) invokeFunc {
+	var once sync.Once
+
+	return func(ctx context.Context, reply interface{}) error {
+		atomic.AddInt32(calls, 1)
+		once.Do(func() { close(started) })
+
+		<-release
+		if ctxErr != nil {
+			*ctxErr = ctx.Err()
+		}
+
+		coin := sdktypes.NewInt64Coin("uatom", 42)
+		*reply.(*bank.QueryBalanceResponse) = bank.QueryBalanceResponse{Balance: &coin}
+
+		return nil
+	}
+}
+
+func TestFlightGroupCoalesces(t *testing.T) {
+	g := newFlightGroup()
+
+	var calls int32
+	started, release := make(chan struct{}), make(chan struct{})
+	invoke := blockingInvoke(&calls, started, release, nil)
+
+	const callers = 10
+
+	var wg sync.WaitGroup
+	var joined int32
+	replies := make([]bank.QueryBalanceResponse, callers)
+	errs := make([]error, callers)
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			atomic.AddInt32(&joined, 1)
+			errs[i] = g.do(context.Background(), "key", &replies[i], invoke)
+		}(i)
+	}
+
+	<-started
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&joined) == callers
+	}, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for i := range replies {
+		require.NoError(t, errs[i])
+		require.Equal(t, "42uatom", replies[i].Balance.String())
+	}
+
+	// Calls once done aren't shared anymore.
+	var reply bank.QueryBalanceResponse
+	require.NoError(t, g.do(context.Background(), "key", &reply, invoke))
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestFlightGroupFirstCallerCanceled(t *testing.T) {
+	g := newFlightGroup()
+
+	var calls int32
+	var callErr error
+	started, release := make(chan struct{}), make(chan struct{})
+	invoke := blockingInvoke(&calls, started, release, &callErr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	first := make(chan error)
+	go func() {
+		var reply bank.QueryBalanceResponse
+		first <- g.do(ctx, "key", &reply, invoke)
+	}()
+	<-started
+
+	second := make(chan error)
+	var reply bank.QueryBalanceResponse
+	go func() {
+		second <- g.do(context.Background(), "key", &reply, invoke)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// The caller which started the call gives up, the others still get the
+	// response.
+	cancel()
+	require.Equal(t, codes.Canceled, status.Code(<-first))
+
+	close(release)
+	require.NoError(t, <-second)
+	require.NoError(t, callErr)
+	require.Equal(t, "42uatom", reply.Balance.String())
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestFlightKey(t *testing.T) {
+	req := &bank.QueryBalanceRequest{Address: "cosmos1abcd", Denom: "uatom"}
+	ctx := context.Background()
+
+	key := func(ctx context.Context, chainName string, req *bank.QueryBalanceRequest) string {
+		k, err := flightKey(ctx, chainName, "/cosmos.bank.v1beta1.Query/Balance", req)
+		require.NoError(t, err)
+		return k
+	}
+
+	same := key(ctx, "cosmos-hub", &bank.QueryBalanceRequest{Address: "cosmos1abcd", Denom: "uatom"})
+	require.Equal(t, key(ctx, "cosmos-hub", req), same)
+
+	atHeight := metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, "42")
+
+	for _, other := range []string{
+		key(ctx, "osmosis", req),
+		key(ctx, "cosmos-hub", &bank.QueryBalanceRequest{Address: "cosmos1abcd", Denom: "uosmo"}),
+		key(atHeight, "cosmos-hub", req),
+	} {
+		require.NotEqual(t, same, other)
+	}
+}
@@ -0,0 +1,88 @@
+package sdkservice
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+)
+
+// Codec extensions chains can enable with the codec_extensions field of the
+// chain registry.
+const (
+	// ExtensionEthermint registers the EthAccount account, the
+	// eth_secp256k1 key and the MsgEthereumTx message types of Ethermint
+	// based chains.
+	ExtensionEthermint = "ethermint"
+)
+
+// codecExtensions register additional account, public key and message
+// implementations in the codec of the chains enabling them.
+var codecExtensions = map[string]func(codectypes.InterfaceRegistry){
+	ExtensionEthermint: registerEthermint,
+}
+
+// CodecExtensions returns the names of the codec extensions chains can
+// enable.
+func CodecExtensions() []string {
+	ret := make([]string, 0, len(codecExtensions))
+	for e := range codecExtensions {
+		ret = append(ret, e)
+	}
+
+	sort.Strings(ret)
+
+	return ret
+}
+
+// chainCodecs caches the codecs of chains enabling codec extensions, per
+// adapter and set of extensions.
+type chainCodecs struct {
+	mu     sync.Mutex
+	codecs map[string]codec.Codec
+}
+
+func newChainCodecs() *chainCodecs {
+	return &chainCodecs{
+		codecs: map[string]codec.Codec{},
+	}
+}
+
+// get returns the codec of adapter extended with the codec extensions of
+// chain.
+func (c *chainCodecs) get(adapter sdkAdapter, chain Chain) codec.Codec {
+	if len(chain.CodecExtensions) == 0 {
+		return adapter.Codec()
+	}
+
+	extensions := append([]string{}, chain.CodecExtensions...)
+	sort.Strings(extensions)
+
+	key := fmt.Sprintf("%T/%s", adapter, strings.Join(extensions, ","))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cdc, ok := c.codecs[key]; ok {
+		return cdc
+	}
+
+	cdc := extendedCodec(adapter, extensions)
+	c.codecs[key] = cdc
+
+	return cdc
+}
+
+// extendedCodec returns a codec holding the types of adapter and the ones
+// of extensions, which must exist.
+func extendedCodec(adapter sdkAdapter, extensions []string) codec.Codec {
+	registry := adapter.NewInterfaceRegistry()
+	for _, e := range extensions {
+		codecExtensions[e](registry)
+	}
+
+	return codec.NewProtoCodec(registry)
+}
@@ -0,0 +1,242 @@
+package sdkservice
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	auth "github.com/cosmos/cosmos-sdk/x/auth/types"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	totalSupplyMethod = "/cosmos.bank.v1beta1.Query/TotalSupply"
+	simulateMethod    = "/cosmos.tx.v1beta1.Service/Simulate"
+)
+
+// conformanceCheck is a check every adapter must pass.
+type conformanceCheck struct {
+	name  string
+	check func(a sdkAdapter) error
+}
+
+// conformanceChecks is the conformance suite every adapter must pass. The
+// checks don't reach any node, upstream calls are answered by a fakeConn.
+var conformanceChecks = []conformanceCheck{
+	{"codec decodes transactions", checkCodec},
+	{"total supply", checkTotalSupply},
+	{"simulate", checkSimulate},
+	{"no hooks for unknown chains", checkDefaultHooks},
+	{"codec extensions", checkCodecExtensions},
+	{"tx not found", checkTxNotFound},
+}
+
+// TestAdapterConformance runs the conformance suite against all the
+// adapters.
+func TestAdapterConformance(t *testing.T) {
+	for _, v := range SupportedSDKVersions() {
+		a := sdkAdapters[v]
+
+		for _, c := range conformanceChecks {
+			c := c
+			t.Run(v+"/"+c.name, func(t *testing.T) {
+				require.NoError(t, c.check(a))
+			})
+		}
+	}
+}
+
+func checkCodec(a sdkAdapter) error {
+	cdc := a.Codec()
+	if cdc == nil {
+		return fmt.Errorf("nil codec")
+	}
+
+	txBytes, err := conformanceTx(cdc)
+	if err != nil {
+		return err
+	}
+
+	var txObj sdktx.Tx
+	if err := cdc.Unmarshal(txBytes, &txObj); err != nil {
+		return fmt.Errorf("cannot unmarshal transaction, %w", err)
+	}
+
+	msgs := txObj.GetMsgs()
+	if len(msgs) != 1 {
+		return fmt.Errorf("expected 1 message, got %d", len(msgs))
+	}
+
+	if _, ok := msgs[0].(*bank.MsgSend); !ok {
+		return fmt.Errorf("expected MsgSend, got %T", msgs[0])
+	}
+
+	return nil
+}
+
+func checkTotalSupply(a sdkAdapter) error {
+	supply := sdktypes.NewCoins(sdktypes.NewInt64Coin("uatom", 1000))
+
+	conn := newFakeConn(map[string]interface{}{
+		totalSupplyMethod: &bank.QueryTotalSupplyResponse{Supply: supply},
+	})
+
+	resp, err := a.TotalSupply(context.Background(), conn, nil)
+	if err != nil {
+		return err
+	}
+
+	if conn.called(totalSupplyMethod) == 0 {
+		return fmt.Errorf("%s not called", totalSupplyMethod)
+	}
+
+	if !resp.Supply.IsEqual(supply) {
+		return fmt.Errorf("expected supply %s, got %s", supply, resp.Supply)
+	}
+
+	return nil
+}
+
+func checkSimulate(a sdkAdapter) error {
+	txBytes, err := conformanceTx(a.Codec())
+	if err != nil {
+		return err
+	}
+
+	conn := newFakeConn(map[string]interface{}{
+		simulateMethod: &sdktx.SimulateResponse{
+			GasInfo: &sdktypes.GasInfo{GasWanted: 2, GasUsed: 1},
+		},
+	})
+
+	resp, err := a.Simulate(context.Background(), conn, txBytes)
+	if err != nil {
+		return err
+	}
+
+	if conn.called(simulateMethod) == 0 {
+		return fmt.Errorf("%s not called", simulateMethod)
+	}
+
+	if resp.GasInfo == nil || resp.GasInfo.GasWanted != 2 || resp.GasInfo.GasUsed != 1 {
+		return fmt.Errorf("unexpected gas info %v", resp.GasInfo)
+	}
+
+	return nil
+}
+
+// checkDefaultHooks checks that chains without custom modules get the
+// cosmos-sdk ones.
+func checkDefaultHooks(a sdkAdapter) error {
+	h := a.Hooks("conformance")
+	if h.MintInflation != nil || h.MintParams != nil || h.MintAnnualProvision != nil || h.Fees != nil {
+		return fmt.Errorf("unknown chain has custom mint or fees hooks")
+	}
+
+	return nil
+}
+
+// checkCodecExtensions checks that codecs extended with all the codec
+// extensions still decode the types of the generation, and decode Ethermint
+// accounts.
+func checkCodecExtensions(a sdkAdapter) error {
+	cdc := extendedCodec(a, CodecExtensions())
+
+	if err := checkCodec(extendedAdapter{a, cdc}); err != nil {
+		return err
+	}
+
+	pk, err := codectypes.NewAnyWithValue(&EthSecp256k1PubKey{
+		Key: []byte{
+			0x02, 0x79, 0xbe, 0x66, 0x7e, 0xf9, 0xdc, 0xbb, 0xac, 0x55, 0xa0, 0x62, 0x95, 0xce, 0x87, 0x0b, 0x07,
+			0x02, 0x9b, 0xfc, 0xdb, 0x2d, 0xce, 0x28, 0xd9, 0x59, 0xf2, 0x81, 0x5b, 0x16, 0xf8, 0x17, 0x98,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	account, err := codectypes.NewAnyWithValue(&EthAccount{
+		BaseAccount: &auth.BaseAccount{AccountNumber: 4, PubKey: pk},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Go through the wire format, as accounts are received from nodes.
+	bz, err := cdc.Marshal(&auth.QueryAccountResponse{Account: account})
+	if err != nil {
+		return fmt.Errorf("cannot marshal Ethermint account, %w", err)
+	}
+
+	var res auth.QueryAccountResponse
+	if err := cdc.Unmarshal(bz, &res); err != nil {
+		return fmt.Errorf("cannot unmarshal Ethermint account, %w", err)
+	}
+
+	var accountI auth.AccountI
+	if err := cdc.UnpackAny(res.Account, &accountI); err != nil {
+		return fmt.Errorf("cannot unpack Ethermint account, %w", err)
+	}
+
+	// The address of the secp256k1 generator point is a well-known one.
+	addr := hex.EncodeToString(accountI.GetPubKey().Address())
+	if accountI.GetAccountNumber() != 4 || addr != "7e5f4552091a69125d5dfcb7b8c2659029395bdf" {
+		return fmt.Errorf("unexpected Ethermint account number %d and address %s", accountI.GetAccountNumber(), addr)
+	}
+
+	return nil
+}
+
+// extendedAdapter is an adapter whose codec is replaced by cdc.
+type extendedAdapter struct {
+	sdkAdapter
+	cdc codec.Codec
+}
+
+func (e extendedAdapter) Codec() codec.Codec {
+	return e.cdc
+}
+
+// conformanceTx returns a transaction holding a MsgSend, encoded with cdc.
+func conformanceTx(cdc codec.Codec) ([]byte, error) {
+	msg, err := codectypes.NewAnyWithValue(&bank.MsgSend{
+		FromAddress: "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu",
+		ToAddress:   "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu",
+		Amount:      sdktypes.NewCoins(sdktypes.NewInt64Coin("uatom", 1)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cdc.Marshal(&sdktx.Tx{
+		Body: &sdktx.TxBody{
+			Messages: []*codectypes.Any{msg},
+		},
+		AuthInfo: &sdktx.AuthInfo{
+			Fee: &sdktx.Fee{},
+		},
+	})
+}
+
+func checkTxNotFound(a sdkAdapter) error {
+	notFound := queryError("tx", status.Errorf(codes.NotFound, "tx not found: ABCD"))
+	if !a.TxNotFound(notFound) {
+		return fmt.Errorf("NotFound error not matched: %s", notFound)
+	}
+
+	unavailable := queryError("tx", status.Error(codes.Unavailable, "connection refused"))
+	if a.TxNotFound(unavailable) {
+		return fmt.Errorf("Unavailable error matched: %s", unavailable)
+	}
+
+	return nil
+}
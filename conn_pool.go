@@ -0,0 +1,179 @@
+package sdkservice
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// DefaultIdleTimeout is the amount of time an upstream connection can stay
+// unused before the pool tears it down.
+const DefaultIdleTimeout = 5 * time.Minute
+
+// ConnPool keeps one long-lived, multiplexed gRPC client connection per
+// upstream endpoint and transport settings, so that requests don't pay a full
+// TCP and HTTP/2 handshake each time they hit a node.
+type ConnPool struct {
+	mu          sync.Mutex
+	conns       map[poolKey]*pooledConn
+	idleTimeout time.Duration
+	dialOpts    []grpc.DialOption
+	closed      bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// poolKey identifies pooled connections. Chains reaching the same endpoint
+// with different credentials get distinct connections.
+type poolKey struct {
+	target    string
+	transport string
+}
+
+type pooledConn struct {
+	conn     *grpc.ClientConn
+	lastUsed time.Time
+	// inUse counts the callers which haven't released the connection yet,
+	// it isn't torn down while they use it.
+	inUse int
+}
+
+// NewConnPool returns a ConnPool which closes connections that have been
+// unused for longer than idleTimeout. A zero idleTimeout disables idle
+// connection teardown.
+func NewConnPool(idleTimeout time.Duration, dialOpts ...grpc.DialOption) *ConnPool {
+	p := &ConnPool{
+		conns:       map[poolKey]*pooledConn{},
+		idleTimeout: idleTimeout,
+		dialOpts:    dialOpts,
+		done:        make(chan struct{}),
+	}
+
+	if idleTimeout > 0 {
+		p.wg.Add(1)
+		go p.reapIdle()
+	}
+
+	return p
+}
+
+// Get returns the connection associated with target and transportKey,
+// dialing it with dialOpts if none exists or if the previous one has been shut
+// down. transportKey identifies dialOpts: callers passing different dial
+// options for the same target must pass different keys.
+// The returned function must be called once done with the connection, which
+// isn't torn down as idle until then.
+func (p *ConnPool) Get(target string, transportKey string, dialOpts ...grpc.DialOption) (*grpc.ClientConn, func(), error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, nil, fmt.Errorf("connection pool is closed")
+	}
+
+	key := poolKey{target: target, transport: transportKey}
+
+	if pc, ok := p.conns[key]; ok {
+		switch pc.conn.GetState() {
+		case connectivity.Shutdown:
+			delete(p.conns, key)
+		case connectivity.TransientFailure:
+			// Don't make callers wait for the backoff timer to expire,
+			// try to reconnect right away.
+			pc.conn.ResetConnectBackoff()
+			fallthrough
+		default:
+			return pc.conn, p.acquire(pc), nil
+		}
+	}
+
+	opts := append(append([]grpc.DialOption{}, p.dialOpts...), dialOpts...)
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create grpc dialer, %w", err)
+	}
+
+	pc := &pooledConn{conn: conn}
+	p.conns[key] = pc
+
+	return conn, p.acquire(pc), nil
+}
+
+// acquire marks pc as in use, until the returned function is called.
+// p.mu must be held.
+func (p *ConnPool) acquire(pc *pooledConn) func() {
+	pc.inUse++
+	pc.lastUsed = time.Now()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+
+			pc.inUse--
+			pc.lastUsed = time.Now()
+		})
+	}
+}
+
+// Close stops idle connection teardown and closes all the pooled connections.
+func (p *ConnPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+
+	p.closed = true
+	close(p.done)
+
+	var firstErr error
+	for key, pc := range p.conns {
+		if err := pc.conn.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("cannot close connection to %s, %w", key.target, err)
+		}
+
+		delete(p.conns, key)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+
+	return firstErr
+}
+
+func (p *ConnPool) reapIdle() {
+	defer p.wg.Done()
+
+	t := time.NewTicker(p.idleTimeout / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case now := <-t.C:
+			p.closeIdle(now)
+		}
+	}
+}
+
+func (p *ConnPool) closeIdle(now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, pc := range p.conns {
+		if pc.inUse > 0 || now.Sub(pc.lastUsed) < p.idleTimeout {
+			continue
+		}
+
+		_ = pc.conn.Close()
+		delete(p.conns, key)
+	}
+}
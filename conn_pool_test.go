@@ -0,0 +1,114 @@
+package sdkservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// pooled returns the number of connections held by p.
+func pooled(p *ConnPool) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.conns)
+}
+
+func TestConnPoolCloseIdle(t *testing.T) {
+	node := newTestNode(t)
+
+	// The teardown isn't run in the background before the test ends.
+	p := NewConnPool(time.Hour)
+	defer p.Close()
+
+	conn, release, err := p.Get(node.target, "", grpc.WithInsecure())
+	require.NoError(t, err)
+	release()
+
+	p.closeIdle(time.Now().Add(30 * time.Minute))
+	require.Equal(t, 1, pooled(p))
+	require.NotEqual(t, connectivity.Shutdown, conn.GetState())
+
+	p.closeIdle(time.Now().Add(2 * time.Hour))
+	require.Zero(t, pooled(p))
+	require.Equal(t, connectivity.Shutdown, conn.GetState())
+
+	// The next call dials again.
+	again, release, err := p.Get(node.target, "", grpc.WithInsecure())
+	require.NoError(t, err)
+	defer release()
+	require.NotSame(t, conn, again)
+}
+
+func TestConnPoolCloseIdleInUse(t *testing.T) {
+	node := newTestNode(t)
+
+	p := NewConnPool(time.Hour)
+	defer p.Close()
+
+	// A call outlasting the idle timeout keeps its connection.
+	conn, release, err := p.Get(node.target, "", grpc.WithInsecure())
+	require.NoError(t, err)
+
+	p.closeIdle(time.Now().Add(2 * time.Hour))
+	require.Equal(t, 1, pooled(p))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	// Releasing it twice doesn't release other users.
+	_, releaseOther, err := p.Get(node.target, "", grpc.WithInsecure())
+	require.NoError(t, err)
+
+	release()
+	release()
+
+	p.closeIdle(time.Now().Add(2 * time.Hour))
+	require.Equal(t, 1, pooled(p))
+
+	releaseOther()
+
+	// The idle time counts from the release.
+	p.closeIdle(time.Now().Add(30 * time.Minute))
+	require.Equal(t, 1, pooled(p))
+
+	p.closeIdle(time.Now().Add(2 * time.Hour))
+	require.Zero(t, pooled(p))
+}
+
+func TestConnPoolStreamInUse(t *testing.T) {
+	node := newTestNode(t)
+
+	u := NewUpstream(NewConnPool(time.Hour))
+	defer u.Close()
+
+	conn := u.Conn(testChain(t, node.target, TLSConfig{}, AuthConfig{}), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := healthpb.NewHealthClient(conn).Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// Streams keep their connection until they end.
+	u.pool.closeIdle(time.Now().Add(2 * time.Hour))
+	require.Equal(t, 1, pooled(u.pool))
+
+	cancel()
+
+	require.Eventually(t, func() bool {
+		u.pool.closeIdle(time.Now().Add(2 * time.Hour))
+		return pooled(u.pool) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
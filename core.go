@@ -0,0 +1,431 @@
+package sdkservice
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distribution "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	mint "github.com/cosmos/cosmos-sdk/x/mint/types"
+	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
+	sdkutilities "github.com/emerishq/sdk-service-meta/gen/sdk_utilities"
+	liquidity "github.com/gravity-devs/liquidity/x/liquidity/types"
+	"google.golang.org/grpc"
+)
+
+// chainQuerier answers the queries of the service for a chain. It owns the
+// queries shared by all cosmos-sdk generations, error mapping and response
+// shaping, and relies on the adapter of the chain generation for the rest.
+type chainQuerier struct {
+	chain Chain
+	conn  grpc.ClientConnInterface
+	sdk   sdkAdapter
+	// cdc is the codec of sdk, extended with the codec extensions of chain.
+	cdc   codec.Codec
+	types *descriptorResolver
+}
+
+// moduleQuery queries a module through grpcConn, returning a response to be
+// JSON encoded.
+type moduleQuery func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error)
+
+// run runs query, returning its JSON encoded response. A nil query means the
+// chain doesn't run module.
+func (q *chainQuerier) run(ctx context.Context, module string, query moduleQuery) ([]byte, error) {
+	if query == nil {
+		return nil, moduleNotPresent(module)
+	}
+
+	resp, err := query(ctx, q.conn)
+	if err != nil {
+		return nil, queryError(module, err)
+	}
+
+	if resp == nil {
+		return nil, newError(KindNotFound, fmt.Errorf("empty response from %s", module))
+	}
+
+	respJSON, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("cannot json marshal response from %s, %w", module, err)
+	}
+
+	return respJSON, nil
+}
+
+func (q *chainQuerier) hooks() chainHooks {
+	return q.sdk.Hooks(q.chain.Name)
+}
+
+func (q *chainQuerier) QuerySupply(ctx context.Context, paginationKey *string) (sdkutilities.Supply2, error) {
+	var key []byte
+	if paginationKey != nil {
+		if k, err := base64.StdEncoding.DecodeString(*paginationKey); err == nil {
+			key = k
+		}
+	}
+
+	suppRes, err := q.sdk.TotalSupply(ctx, q.conn, key)
+	if err != nil {
+		return sdkutilities.Supply2{}, queryError("bank", err)
+	}
+
+	ret := sdkutilities.Supply2{
+		Pagination: &sdkutilities.Pagination{},
+	}
+
+	if suppRes.Pagination != nil {
+		var nextKey = base64.StdEncoding.EncodeToString(suppRes.Pagination.NextKey)
+		var total = strconv.FormatUint(suppRes.Pagination.Total, 10)
+
+		ret.Pagination = &sdkutilities.Pagination{
+			NextKey: &nextKey,
+			Total:   &total,
+		}
+	}
+
+	for _, s := range suppRes.Supply {
+		ret.Coins = append(ret.Coins, &sdkutilities.Coin{
+			Denom:  s.Denom,
+			Amount: s.Amount.String(),
+		})
+	}
+
+	return ret, nil
+}
+
+func (q *chainQuerier) SupplyDenom(ctx context.Context, denom *string) (*sdkutilities.Supply2, error) {
+	bankQuery := bank.NewQueryClient(q.conn)
+	suppRes, err := bankQuery.SupplyOf(ctx, &bank.QuerySupplyOfRequest{Denom: *denom})
+	if err != nil {
+		return &sdkutilities.Supply2{}, queryError("bank", err)
+	}
+
+	ret := sdkutilities.Supply2{Coins: []*sdkutilities.Coin{{Denom: *denom, Amount: suppRes.Amount.String()}}}
+
+	return &ret, nil
+}
+
+func (q *chainQuerier) GetTxFromHash(ctx context.Context, hash string) ([]byte, error) {
+	txClient := sdktx.NewServiceClient(q.conn)
+
+	grpcRes, err := txClient.GetTx(ctx, &sdktx.GetTxRequest{Hash: hash})
+	if err != nil {
+		return nil, queryError("tx", err)
+	}
+
+	return q.marshalJSON(ctx, grpcRes)
+}
+
+func (q *chainQuerier) Block(ctx context.Context, height int64) (sdkutilities.BlockData, error) {
+	respJSON, err := q.run(ctx, "tendermint", func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+		return tmservice.NewServiceClient(grpcConn).GetBlockByHeight(ctx, &tmservice.GetBlockByHeightRequest{
+			Height: height,
+		})
+	})
+	if err != nil {
+		return sdkutilities.BlockData{}, err
+	}
+
+	return sdkutilities.BlockData{
+		Height: height,
+		Block:  respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) LiquidityParams(ctx context.Context) (sdkutilities.LiquidityParams2, error) {
+	respJSON, err := q.run(ctx, "liquidity", func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+		return liquidity.NewQueryClient(grpcConn).Params(ctx, &liquidity.QueryParamsRequest{})
+	})
+	if err != nil {
+		return sdkutilities.LiquidityParams2{}, err
+	}
+
+	return sdkutilities.LiquidityParams2{
+		LiquidityParams: respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) LiquidityPools(ctx context.Context) (sdkutilities.LiquidityPools2, error) {
+	respJSON, err := q.run(ctx, "liquidity", func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+		return liquidity.NewQueryClient(grpcConn).LiquidityPools(ctx, &liquidity.QueryLiquidityPoolsRequest{})
+	})
+	if err != nil {
+		return sdkutilities.LiquidityPools2{}, err
+	}
+
+	return sdkutilities.LiquidityPools2{
+		LiquidityPools: respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) MintInflation(ctx context.Context) (sdkutilities.MintInflation2, error) {
+	query := q.hooks().MintInflation
+	if query == nil {
+		query = func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+			return mint.NewQueryClient(grpcConn).Inflation(ctx, &mint.QueryInflationRequest{})
+		}
+	}
+
+	respJSON, err := q.run(ctx, "mint", query)
+	if err != nil {
+		return sdkutilities.MintInflation2{}, err
+	}
+
+	return sdkutilities.MintInflation2{
+		MintInflation: respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) MintParams(ctx context.Context) (sdkutilities.MintParams2, error) {
+	query := q.hooks().MintParams
+	if query == nil {
+		query = func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+			return mint.NewQueryClient(grpcConn).Params(ctx, &mint.QueryParamsRequest{})
+		}
+	}
+
+	respJSON, err := q.run(ctx, "mint", query)
+	if err != nil {
+		return sdkutilities.MintParams2{}, err
+	}
+
+	return sdkutilities.MintParams2{
+		MintParams: respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) MintAnnualProvision(ctx context.Context) (sdkutilities.MintAnnualProvision2, error) {
+	query := q.hooks().MintAnnualProvision
+	if query == nil {
+		query = func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+			return mint.NewQueryClient(grpcConn).AnnualProvisions(ctx, &mint.QueryAnnualProvisionsRequest{})
+		}
+	}
+
+	respJSON, err := q.run(ctx, "mint", query)
+	if err != nil {
+		return sdkutilities.MintAnnualProvision2{}, err
+	}
+
+	return sdkutilities.MintAnnualProvision2{
+		MintAnnualProvision: respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) EmoneyInflation(ctx context.Context) (sdkutilities.EmoneyInflation2, error) {
+	query := q.hooks().EmoneyInflation
+	if query == nil {
+		return sdkutilities.EmoneyInflation2{}, moduleNotPresent("inflation")
+	}
+
+	state, err := query(ctx, q.conn)
+	if err != nil {
+		return sdkutilities.EmoneyInflation2{}, queryError("inflation", err)
+	}
+
+	return sdkutilities.EmoneyInflation2{
+		State: state,
+	}, nil
+}
+
+func (q *chainQuerier) MintEpochProvisions(ctx context.Context) (sdkutilities.MintEpochProvisions2, error) {
+	respJSON, err := q.run(ctx, "mint epoch provisions", q.hooks().MintEpochProvisions)
+	if err != nil {
+		return sdkutilities.MintEpochProvisions2{}, err
+	}
+
+	return sdkutilities.MintEpochProvisions2{
+		MintEpochProvisions: respJSON,
+	}, nil
+}
+
+// bech32Address encodes hexAddress with the bech32hrp prefix.
+func bech32Address(hexAddress string, bech32hrp string) (string, error) {
+	addrBytes, err := hex.DecodeString(hexAddress)
+	if err != nil {
+		return "", newError(KindInvalidArgument, fmt.Errorf("invalid hex address %s, %w", hexAddress, err))
+	}
+
+	addr, err := bech32.ConvertAndEncode(bech32hrp, addrBytes)
+	if err != nil {
+		return "", newError(KindInvalidArgument, fmt.Errorf("cannot encode address with prefix %s, %w", bech32hrp, err))
+	}
+
+	return addr, nil
+}
+
+// AccountNumbers returns the account and sequence numbers of address.
+// Addresses without an account, which never received funds, fail as
+// KindNotFound.
+func (q *chainQuerier) AccountNumbers(ctx context.Context, address AccountAddress) (sdkutilities.AccountNumbers2, error) {
+	addr, err := q.bech32Address(address)
+	if err != nil {
+		return sdkutilities.AccountNumbers2{}, err
+	}
+
+	accountI, err := q.account(ctx, addr)
+	if err != nil {
+		return sdkutilities.AccountNumbers2{}, err
+	}
+
+	if accountI == nil {
+		return sdkutilities.AccountNumbers2{}, newError(KindNotFound, fmt.Errorf("account %s not found", addr))
+	}
+
+	ret := sdkutilities.AccountNumbers2{}
+
+	ret.AccountNumber = int64(accountI.GetAccountNumber())
+	ret.SequenceNumber = int64(accountI.GetSequence())
+	ret.Bech32Address = addr
+
+	return ret, nil
+}
+
+func (q *chainQuerier) DelegatorRewards(ctx context.Context, address AccountAddress) (sdkutilities.DelegatorRewards2, error) {
+	addr, err := q.bech32Address(address)
+	if err != nil {
+		return sdkutilities.DelegatorRewards2{}, err
+	}
+
+	distributionQuery := distribution.NewQueryClient(q.conn)
+
+	res, err := distributionQuery.DelegationTotalRewards(ctx, &distribution.QueryDelegationTotalRewardsRequest{
+		DelegatorAddress: addr,
+	})
+
+	if err != nil {
+		return sdkutilities.DelegatorRewards2{}, queryError("distribution", err)
+	}
+
+	ret := sdkutilities.DelegatorRewards2{}
+
+	for _, d := range res.Rewards {
+		r := &sdkutilities.DelegationDelegatorReward{
+			ValidatorAddress: d.ValidatorAddress,
+		}
+
+		for _, rr := range d.Reward {
+			r.Rewards = append(r.Rewards, sdkDecCoinToUtilCoin(rr))
+		}
+
+		ret.Rewards = append(ret.Rewards, r)
+	}
+
+	for _, d := range res.Total {
+		ret.Total = append(ret.Total, sdkDecCoinToUtilCoin(d))
+	}
+
+	return ret, nil
+}
+
+func (q *chainQuerier) FeeEstimate(ctx context.Context, txBytes []byte) (sdkutilities.Simulation, error) {
+	simRes, err := q.sdk.Simulate(ctx, q.conn, txBytes)
+	if err != nil {
+		return sdkutilities.Simulation{}, queryError("tx", err)
+	}
+
+	ret := sdkutilities.Simulation{
+		GasWanted: simRes.GasInfo.GasWanted,
+		GasUsed:   simRes.GasInfo.GasUsed,
+	}
+
+	if fees := q.hooks().Fees; fees != nil {
+		coins, err := fees(ctx, q.chain, txBytes)
+		if err != nil {
+			return sdkutilities.Simulation{}, err
+		}
+
+		ret.Fees = coins
+	}
+
+	return ret, nil
+}
+
+func sdkDecCoinToUtilCoin(c sdktypes.DecCoin) *sdkutilities.Coin {
+	return &sdkutilities.Coin{
+		Denom:  c.Denom,
+		Amount: c.Amount.String(),
+	}
+}
+
+func (q *chainQuerier) StakingParams(ctx context.Context) (sdkutilities.StakingParams2, error) {
+	respJSON, err := q.run(ctx, "staking", func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+		return staking.NewQueryClient(grpcConn).Params(ctx, &staking.QueryParamsRequest{})
+	})
+	if err != nil {
+		return sdkutilities.StakingParams2{}, err
+	}
+
+	return sdkutilities.StakingParams2{
+		StakingParams: respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) StakingPool(ctx context.Context) (sdkutilities.StakingPool2, error) {
+	respJSON, err := q.run(ctx, "staking", func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+		return staking.NewQueryClient(grpcConn).Pool(ctx, &staking.QueryPoolRequest{})
+	})
+	if err != nil {
+		return sdkutilities.StakingPool2{}, err
+	}
+
+	return sdkutilities.StakingPool2{
+		StakingPool: respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) DistributionParams(ctx context.Context) (sdkutilities.DistributionParams2, error) {
+	respJSON, err := q.run(ctx, "distribution", func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+		return distribution.NewQueryClient(grpcConn).Params(ctx, &distribution.QueryParamsRequest{})
+	})
+	if err != nil {
+		return sdkutilities.DistributionParams2{}, err
+	}
+
+	return sdkutilities.DistributionParams2{
+		DistributionParams: respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) BudgetParams(ctx context.Context) (sdkutilities.BudgetParams2, error) {
+	respJSON, err := q.run(ctx, "budget", q.hooks().BudgetParams)
+	if err != nil {
+		return sdkutilities.BudgetParams2{}, err
+	}
+
+	return sdkutilities.BudgetParams2{
+		BudgetParams: respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) OsmoPools(ctx context.Context) (sdkutilities.OsmoPools2, error) {
+	respJSON, err := q.run(ctx, "gamm", q.hooks().OsmoPools)
+	if err != nil {
+		return sdkutilities.OsmoPools2{}, err
+	}
+
+	return sdkutilities.OsmoPools2{
+		OsmoPools: respJSON,
+	}, nil
+}
+
+func (q *chainQuerier) CrescentPools(ctx context.Context) (sdkutilities.CrescentPools2, error) {
+	respJSON, err := q.run(ctx, "liquidity", q.hooks().CrescentPools)
+	if err != nil {
+		return sdkutilities.CrescentPools2{}, err
+	}
+
+	return sdkutilities.CrescentPools2{
+		CrescentPools: respJSON,
+	}, nil
+}
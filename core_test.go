@@ -0,0 +1,272 @@
+package sdkservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	distribution "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	mint "github.com/cosmos/cosmos-sdk/x/mint/types"
+	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
+	sdkutilities "github.com/emerishq/sdk-service-meta/gen/sdk_utilities"
+	gogotypes "github.com/gogo/protobuf/types"
+	liquidity "github.com/gravity-devs/liquidity/x/liquidity/types"
+	"github.com/stretchr/testify/require"
+	budget "github.com/tendermint/budget/x/budget/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func testQuerier(chainName string, sdkVersion string, conn *fakeConn) *chainQuerier {
+	sdk := sdkAdapters[sdkVersion]
+
+	return &chainQuerier{
+		chain: Chain{Name: chainName, SDKVersion: sdkVersion},
+		conn:  conn,
+		sdk:   sdk,
+		cdc:   sdk.Codec(),
+		types: newDescriptorResolver(),
+	}
+}
+
+// emoneyInflationResponse returns a QueryInflationResponse of e-money with
+// an inflation per denom.
+func emoneyInflationResponse(t *testing.T, lastApplied time.Time, height int64, inflation map[string]string) []byte {
+	ts, err := gogotypes.TimestampProto(lastApplied)
+	require.NoError(t, err)
+
+	tsBytes, err := ts.Marshal()
+	require.NoError(t, err)
+
+	var state []byte
+	state = protowire.AppendTag(state, 1, protowire.BytesType)
+	state = protowire.AppendBytes(state, tsBytes)
+	state = protowire.AppendTag(state, 2, protowire.BytesType)
+	state = protowire.AppendString(state, sdktypes.NewInt(height).String())
+
+	for denom, rate := range inflation {
+		infl, err := sdktypes.MustNewDecFromStr(rate).Marshal()
+		require.NoError(t, err)
+
+		accum, err := sdktypes.ZeroDec().Marshal()
+		require.NoError(t, err)
+
+		var asset []byte
+		asset = protowire.AppendTag(asset, 1, protowire.BytesType)
+		asset = protowire.AppendString(asset, denom)
+		asset = protowire.AppendTag(asset, 2, protowire.BytesType)
+		asset = protowire.AppendBytes(asset, infl)
+		asset = protowire.AppendTag(asset, 3, protowire.BytesType)
+		asset = protowire.AppendBytes(asset, accum)
+
+		state = protowire.AppendTag(state, 3, protowire.BytesType)
+		state = protowire.AppendBytes(state, asset)
+	}
+
+	var resp []byte
+	resp = protowire.AppendTag(resp, 1, protowire.BytesType)
+	return protowire.AppendBytes(resp, state)
+}
+
+// TestQueryFailures checks that every module query either answers, fails
+// as the module not being present, or surfaces upstream failures, and never
+// answers an empty result.
+func TestQueryFailures(t *testing.T) {
+	emoneyResp := emoneyInflationResponse(t, time.Unix(1650000000, 0), 42, map[string]string{"ungm": "0.1", "eeur": "0"})
+
+	tests := []struct {
+		name       string
+		chain      string
+		sdkVersion string
+		method     string
+		reply      interface{}
+		query      func(q *chainQuerier) ([]byte, error)
+	}{
+		{
+			name:   "liquidity params",
+			method: "/tendermint.liquidity.v1beta1.Query/Params",
+			reply:  &liquidity.QueryParamsResponse{Params: liquidity.DefaultParams()},
+			query: func(q *chainQuerier) ([]byte, error) {
+				ret, err := q.LiquidityParams(context.Background())
+				return ret.LiquidityParams, err
+			},
+		},
+		{
+			name:   "liquidity pools",
+			method: "/tendermint.liquidity.v1beta1.Query/LiquidityPools",
+			reply:  &liquidity.QueryLiquidityPoolsResponse{Pools: []liquidity.Pool{{Id: 1}}},
+			query: func(q *chainQuerier) ([]byte, error) {
+				ret, err := q.LiquidityPools(context.Background())
+				return ret.LiquidityPools, err
+			},
+		},
+		{
+			name:   "staking params",
+			method: "/cosmos.staking.v1beta1.Query/Params",
+			reply:  &staking.QueryParamsResponse{Params: staking.DefaultParams()},
+			query: func(q *chainQuerier) ([]byte, error) {
+				ret, err := q.StakingParams(context.Background())
+				return ret.StakingParams, err
+			},
+		},
+		{
+			name:   "staking pool",
+			method: "/cosmos.staking.v1beta1.Query/Pool",
+			reply:  &staking.QueryPoolResponse{Pool: staking.NewPool(sdktypes.NewInt(1), sdktypes.NewInt(2))},
+			query: func(q *chainQuerier) ([]byte, error) {
+				ret, err := q.StakingPool(context.Background())
+				return ret.StakingPool, err
+			},
+		},
+		{
+			name:   "distribution params",
+			method: "/cosmos.distribution.v1beta1.Query/Params",
+			reply:  &distribution.QueryParamsResponse{Params: distribution.DefaultParams()},
+			query: func(q *chainQuerier) ([]byte, error) {
+				ret, err := q.DistributionParams(context.Background())
+				return ret.DistributionParams, err
+			},
+		},
+		{
+			name:   "budget params",
+			method: "/cosmos.budget.v1beta1.Query/Params",
+			reply:  &budget.QueryParamsResponse{Params: budget.DefaultParams()},
+			query: func(q *chainQuerier) ([]byte, error) {
+				ret, err := q.BudgetParams(context.Background())
+				return ret.BudgetParams, err
+			},
+		},
+		{
+			name:   "mint inflation",
+			method: "/cosmos.mint.v1beta1.Query/Inflation",
+			reply:  &mint.QueryInflationResponse{Inflation: sdktypes.NewDecWithPrec(7, 2)},
+			query: func(q *chainQuerier) ([]byte, error) {
+				ret, err := q.MintInflation(context.Background())
+				return ret.MintInflation, err
+			},
+		},
+		{
+			name:       "emoney mint inflation",
+			chain:      emoneyChainName,
+			sdkVersion: SDKv42,
+			method:     emoneyInflationMethod,
+			reply:      emoneyResp,
+			query: func(q *chainQuerier) ([]byte, error) {
+				ret, err := q.MintInflation(context.Background())
+				return ret.MintInflation, err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		if tt.chain == "" {
+			tt.chain = "cosmos-hub"
+		}
+		if tt.sdkVersion == "" {
+			tt.sdkVersion = SDKv44
+		}
+
+		t.Run(tt.name+"/module missing", func(t *testing.T) {
+			_, err := tt.query(testQuerier(tt.chain, tt.sdkVersion, newFakeConn(nil)))
+			require.Equal(t, KindModuleNotPresent, ClassifyError(err).Kind)
+			require.True(t, errors.Is(err, ErrModuleNotPresent))
+		})
+
+		t.Run(tt.name+"/node unreachable", func(t *testing.T) {
+			conn := newFakeConn(map[string]interface{}{
+				tt.method: status.Error(codes.Unavailable, "connection refused"),
+			})
+
+			ret, err := tt.query(testQuerier(tt.chain, tt.sdkVersion, conn))
+			require.Nil(t, ret)
+			require.Equal(t, KindUpstreamUnavailable, ClassifyError(err).Kind)
+		})
+
+		t.Run(tt.name+"/success", func(t *testing.T) {
+			conn := newFakeConn(map[string]interface{}{tt.method: tt.reply})
+
+			ret, err := tt.query(testQuerier(tt.chain, tt.sdkVersion, conn))
+			require.NoError(t, err)
+			require.NotEmpty(t, ret)
+			require.Equal(t, 1, conn.called(tt.method))
+		})
+	}
+}
+
+func TestEmoneyMintQueriesNotPresent(t *testing.T) {
+	conn := newFakeConn(nil)
+	q := testQuerier(emoneyChainName, SDKv42, conn)
+
+	_, err := q.MintParams(context.Background())
+	require.Equal(t, KindModuleNotPresent, ClassifyError(err).Kind)
+
+	_, err = q.MintAnnualProvision(context.Background())
+	require.Equal(t, KindModuleNotPresent, ClassifyError(err).Kind)
+
+	_, err = q.MintEpochProvisions(context.Background())
+	require.Equal(t, KindModuleNotPresent, ClassifyError(err).Kind)
+}
+
+func TestEmoneyInflation(t *testing.T) {
+	lastApplied := time.Date(2022, 4, 15, 5, 20, 0, 0, time.UTC)
+
+	t.Run("module missing", func(t *testing.T) {
+		// Chains other than e-money don't run its inflation module, and
+		// aren't queried.
+		conn := newFakeConn(nil)
+		_, err := testQuerier("cosmos-hub", SDKv44, conn).EmoneyInflation(context.Background())
+		require.Equal(t, KindModuleNotPresent, ClassifyError(err).Kind)
+		require.Zero(t, conn.called(emoneyInflationMethod))
+
+		_, err = testQuerier(emoneyChainName, SDKv42, conn).EmoneyInflation(context.Background())
+		require.Equal(t, KindModuleNotPresent, ClassifyError(err).Kind)
+	})
+
+	t.Run("node unreachable", func(t *testing.T) {
+		conn := newFakeConn(map[string]interface{}{
+			emoneyInflationMethod: status.Error(codes.Unavailable, "connection refused"),
+		})
+
+		ret, err := testQuerier(emoneyChainName, SDKv42, conn).EmoneyInflation(context.Background())
+		require.Equal(t, KindUpstreamUnavailable, ClassifyError(err).Kind)
+		require.Nil(t, ret.State)
+	})
+
+	t.Run("malformed response", func(t *testing.T) {
+		conn := newFakeConn(map[string]interface{}{
+			emoneyInflationMethod: []byte{0x0a, 0x05},
+		})
+
+		_, err := testQuerier(emoneyChainName, SDKv42, conn).EmoneyInflation(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		conn := newFakeConn(map[string]interface{}{
+			emoneyInflationMethod: emoneyInflationResponse(t, lastApplied, 42, map[string]string{"ungm": "0.1"}),
+		})
+
+		ret, err := testQuerier(emoneyChainName, SDKv42, conn).EmoneyInflation(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, &sdkutilities.EmoneyState{
+			LastApplied:       "2022-04-15T05:20:00Z",
+			LastAppliedHeight: "42",
+			Assets: []*sdkutilities.EmoneyAsset{
+				{Denom: "ungm", Inflation: "0.100000000000000000", Accum: "0.000000000000000000"},
+			},
+		}, ret.State)
+	})
+
+	t.Run("no ungm inflation", func(t *testing.T) {
+		conn := newFakeConn(map[string]interface{}{
+			emoneyInflationMethod: emoneyInflationResponse(t, lastApplied, 42, map[string]string{"eeur": "0.01"}),
+		})
+
+		_, err := testQuerier(emoneyChainName, SDKv42, conn).MintInflation(context.Background())
+		require.Equal(t, KindNotFound, ClassifyError(err).Kind)
+	})
+}
@@ -0,0 +1,108 @@
+package sdkservice
+
+import (
+	"context"
+	"fmt"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The liquidity types of Crescent are declared here with the same wire format
+// as the Crescent ones rather than importing its liquidity module, which
+// registers its errors in the same codespace as the Gravity DEX one and makes
+// the service panic at init.
+
+const crescentPoolsMethod = "/crescent.liquidity.v1beta1.Query/Pools"
+
+// crescentPool has the fields and the JSON encoding of the PoolResponse of
+// Crescent.
+type crescentPool struct {
+	ID                    uint64         `json:"id,omitempty"`
+	PairID                uint64         `json:"pair_id,omitempty"`
+	ReserveAddress        string         `json:"reserve_address,omitempty"`
+	PoolCoinDenom         string         `json:"pool_coin_denom,omitempty"`
+	Balances              sdktypes.Coins `json:"balances"`
+	LastDepositRequestID  uint64         `json:"last_deposit_request_id,omitempty"`
+	LastWithdrawRequestID uint64         `json:"last_withdraw_request_id,omitempty"`
+}
+
+func crescentPools(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+	var req, resp []byte
+	if err := grpcConn.Invoke(ctx, crescentPoolsMethod, &req, &resp, grpc.ForceCodec(rawCodec{})); err != nil {
+		return nil, err
+	}
+
+	values, err := protoFields(resp, 1)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode crescent pools, %w", err)
+	}
+
+	ret := make([]crescentPool, 0, len(values))
+	for _, v := range values {
+		p, err := decodeCrescentPool(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode crescent pool, %w", err)
+		}
+
+		ret = append(ret, p)
+	}
+
+	return ret, nil
+}
+
+func decodeCrescentPool(b []byte) (crescentPool, error) {
+	var p crescentPool
+
+	for len(b) > 0 {
+		num, typ, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			return crescentPool{}, protowire.ParseError(l)
+		}
+		b = b[l:]
+
+		var (
+			varint uint64
+			bytes  []byte
+		)
+
+		switch typ {
+		case protowire.VarintType:
+			varint, l = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			bytes, l = protowire.ConsumeBytes(b)
+		default:
+			l = protowire.ConsumeFieldValue(num, typ, b)
+		}
+
+		if l < 0 {
+			return crescentPool{}, protowire.ParseError(l)
+		}
+		b = b[l:]
+
+		switch num {
+		case 1:
+			p.ID = varint
+		case 2:
+			p.PairID = varint
+		case 3:
+			p.ReserveAddress = string(bytes)
+		case 4:
+			p.PoolCoinDenom = string(bytes)
+		case 5:
+			var c sdktypes.Coin
+			if err := c.Unmarshal(bytes); err != nil {
+				return crescentPool{}, err
+			}
+
+			p.Balances = append(p.Balances, c)
+		case 6:
+			p.LastDepositRequestID = varint
+		case 7:
+			p.LastWithdrawRequestID = varint
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,90 @@
+package sdkservice
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	crescentmint "github.com/crescent-network/crescent/x/mint/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestCrescentPools(t *testing.T) {
+	coin, err := (&sdktypes.Coin{Denom: "ucre", Amount: sdktypes.NewInt(42)}).Marshal()
+	require.NoError(t, err)
+
+	var pool []byte
+	pool = protowire.AppendTag(pool, 1, protowire.VarintType)
+	pool = protowire.AppendVarint(pool, 3)
+	pool = protowire.AppendTag(pool, 2, protowire.VarintType)
+	pool = protowire.AppendVarint(pool, 1)
+	pool = protowire.AppendTag(pool, 4, protowire.BytesType)
+	pool = protowire.AppendString(pool, "pool3")
+	pool = protowire.AppendTag(pool, 5, protowire.BytesType)
+	pool = protowire.AppendBytes(pool, coin)
+	// Unknown fields are skipped.
+	pool = protowire.AppendTag(pool, 42, protowire.Fixed32Type)
+	pool = protowire.AppendFixed32(pool, 1)
+
+	var resp []byte
+	resp = protowire.AppendTag(resp, 1, protowire.BytesType)
+	resp = protowire.AppendBytes(resp, pool)
+
+	conn := newFakeConn(map[string]interface{}{crescentPoolsMethod: resp})
+
+	pools, err := crescentPools(context.Background(), conn)
+	require.NoError(t, err)
+
+	out, err := json.Marshal(pools)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"id":3,"pair_id":1,"pool_coin_denom":"pool3","balances":[{"denom":"ucre","amount":"42"}]}]`, string(out))
+
+	_, err = crescentPools(context.Background(), newFakeConn(map[string]interface{}{crescentPoolsMethod: []byte{0x0a, 0x05}}))
+	require.Error(t, err)
+}
+
+func TestCrescentMintInflation(t *testing.T) {
+	now := time.Now()
+	year := 365 * 24 * time.Hour
+
+	past := crescentmint.InflationSchedule{
+		StartTime: now.Add(-2 * year),
+		EndTime:   now.Add(-year),
+		Amount:    sdktypes.NewInt(100000000000000),
+	}
+	current := crescentmint.InflationSchedule{
+		StartTime: now.Add(-year),
+		EndTime:   now.Add(year),
+		Amount:    sdktypes.NewInt(75000000000000),
+	}
+	future := crescentmint.InflationSchedule{
+		StartTime: now.Add(year),
+		EndTime:   now.Add(2 * year),
+		Amount:    sdktypes.NewInt(50000000000000),
+	}
+
+	conn := func(schedules ...crescentmint.InflationSchedule) *fakeConn {
+		return newFakeConn(map[string]interface{}{
+			"/crescent.mint.v1beta1.Query/Params": &crescentmint.QueryParamsResponse{
+				Params: crescentmint.Params{MintDenom: "ucre", InflationSchedules: schedules},
+			},
+		})
+	}
+
+	// Inflations below 100% aren't rounded down.
+	ret, err := crescentMintInflation(context.Background(), conn(past, current, future))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"inflation":"0.250000000000000000"}`, string(ret.(json.RawMessage)))
+
+	for name, schedules := range map[string][]crescentmint.InflationSchedule{
+		"none":   nil,
+		"ended":  {past},
+		"future": {future},
+	} {
+		_, err := crescentMintInflation(context.Background(), conn(schedules...))
+		require.Equal(t, KindNotFound, ClassifyError(err).Kind, name)
+	}
+}
@@ -0,0 +1,475 @@
+package sdkservice
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	gogoproto "github.com/gogo/protobuf/proto"
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// descriptorResolver resolves the protobuf types of a chain, first from the
+// types compiled in the service, then from the gRPC reflection service of
+// the chain nodes. Resolved descriptors are kept for the lifetime of the
+// service.
+type descriptorResolver struct {
+	mu    sync.Mutex
+	files *protoregistry.Files
+
+	// missing holds the files the nodes answered they don't serve.
+	missing map[string]bool
+}
+
+func newDescriptorResolver() *descriptorResolver {
+	return &descriptorResolver{
+		files:   &protoregistry.Files{},
+		missing: map[string]bool{},
+	}
+}
+
+// descriptorResolvers holds the descriptor resolver of each chain.
+type descriptorResolvers struct {
+	mu        sync.Mutex
+	resolvers map[string]*descriptorResolver
+}
+
+func newDescriptorResolvers() *descriptorResolvers {
+	return &descriptorResolvers{
+		resolvers: map[string]*descriptorResolver{},
+	}
+}
+
+func (d *descriptorResolvers) get(chainName string) *descriptorResolver {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	r, ok := d.resolvers[chainName]
+	if !ok {
+		r = newDescriptorResolver()
+		d.resolvers[chainName] = r
+	}
+
+	return r
+}
+
+// find returns the descriptor of the message named name, asking the nodes
+// behind grpcConn if it isn't compiled in the service. r.mu isn't held while
+// the nodes are asked, only while the answers are registered.
+func (r *descriptorResolver) find(ctx context.Context, grpcConn grpc.ClientConnInterface, name protoreflect.FullName) (protoreflect.MessageDescriptor, error) {
+	if md, ok := r.lookup(name); ok {
+		return md, nil
+	}
+
+	if t := gogoproto.MessageType(string(name)); t != nil {
+		fd, err := staticFile(t)
+		if err == nil {
+			r.resolve(ctx, grpcConn, []*descriptorpb.FileDescriptorProto{fd})
+			if md, ok := r.lookup(name); ok {
+				return md, nil
+			}
+		}
+	}
+
+	fds, err := reflectionFiles(ctx, grpcConn, &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: string(name),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.resolve(ctx, grpcConn, fds)
+
+	if md, ok := r.lookup(name); ok {
+		return md, nil
+	}
+
+	return nil, fmt.Errorf("cannot resolve type %s", name)
+}
+
+func (r *descriptorResolver) lookup(name protoreflect.FullName) (protoreflect.MessageDescriptor, bool) {
+	md, ok := r.lookupDescriptor(name).(protoreflect.MessageDescriptor)
+	return md, ok
+}
+
+// findService returns the descriptor of the service named name, first from
+// the files compiled in the service, then as declared by the nodes behind
+// grpcConn. The files it depends on are taken from the service types when
+// compiled in.
+func (r *descriptorResolver) findService(ctx context.Context, grpcConn grpc.ClientConnInterface, name protoreflect.FullName) (protoreflect.ServiceDescriptor, error) {
+	if sd, ok := r.lookupDescriptor(name).(protoreflect.ServiceDescriptor); ok {
+		return sd, nil
+	}
+
+	if fd, ok := staticServiceFile(name); ok {
+		r.resolve(ctx, grpcConn, []*descriptorpb.FileDescriptorProto{fd})
+		if sd, ok := r.lookupDescriptor(name).(protoreflect.ServiceDescriptor); ok {
+			return sd, nil
+		}
+	}
+
+	fds, err := reflectionFiles(ctx, grpcConn, &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: string(name),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.resolve(ctx, grpcConn, fds)
+
+	if sd, ok := r.lookupDescriptor(name).(protoreflect.ServiceDescriptor); ok {
+		return sd, nil
+	}
+
+	return nil, newError(KindNotFound, fmt.Errorf("unknown service %s", name))
+}
+
+func (r *descriptorResolver) lookupDescriptor(name protoreflect.FullName) protoreflect.Descriptor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, err := r.files.FindDescriptorByName(name)
+	if err != nil {
+		return nil
+	}
+
+	return d
+}
+
+// resolve registers fds, after fetching the dependencies which aren't known
+// yet.
+func (r *descriptorResolver) resolve(ctx context.Context, grpcConn grpc.ClientConnInterface, fds []*descriptorpb.FileDescriptorProto) {
+	known := map[string]*descriptorpb.FileDescriptorProto{}
+	for _, fd := range fds {
+		known[fd.GetName()] = fd
+	}
+
+	r.fetchDependencies(ctx, grpcConn, known)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, fd := range fds {
+		r.register(fd, known)
+	}
+}
+
+// fetchDependencies adds to known the files the known files depend on,
+// taken from the service types or fetched from the nodes. Files already
+// registered, well-known ones and the ones the nodes don't serve are
+// skipped.
+func (r *descriptorResolver) fetchDependencies(ctx context.Context, grpcConn grpc.ClientConnInterface, known map[string]*descriptorpb.FileDescriptorProto) {
+	queue := make([]string, 0, len(known))
+	for name := range known {
+		queue = append(queue, name)
+	}
+
+	for len(queue) > 0 {
+		fd := known[queue[0]]
+		queue = queue[1:]
+
+		for _, dep := range fd.GetDependency() {
+			if _, ok := known[dep]; ok || r.skipFile(dep) {
+				continue
+			}
+
+			if gz := gogoproto.FileDescriptor(dep); gz != nil {
+				if depFd, err := unzipFile(gz); err == nil {
+					known[dep] = depFd
+					queue = append(queue, dep)
+					continue
+				}
+			}
+
+			fetched, err := reflectionFiles(ctx, grpcConn, &rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{
+					FileByFilename: dep,
+				},
+			})
+			if err != nil {
+				// Only the nodes answering they don't have the file are
+				// remembered, other failures are tried again next time.
+				if ClassifyError(err).Kind == KindNotFound {
+					r.setMissing(dep)
+				}
+
+				continue
+			}
+
+			for _, f := range fetched {
+				if _, ok := known[f.GetName()]; !ok {
+					known[f.GetName()] = f
+					queue = append(queue, f.GetName())
+				}
+			}
+
+			if known[dep] == nil {
+				r.setMissing(dep)
+			}
+		}
+	}
+}
+
+// skipFile returns whether the file at path doesn't need to be fetched.
+func (r *descriptorResolver) skipFile(path string) bool {
+	if _, err := protoregistry.GlobalFiles.FindFileByPath(path); err == nil {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.files.FindFileByPath(path); err == nil {
+		return true
+	}
+
+	return r.missing[path]
+}
+
+func (r *descriptorResolver) setMissing(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.missing[path] = true
+}
+
+// register registers fd after its dependencies, which are looked up in
+// known and the well-known types. Files which can't be registered are
+// skipped, the types they declare are reported as unresolved by find.
+// r.mu must be held.
+func (r *descriptorResolver) register(fd *descriptorpb.FileDescriptorProto, known map[string]*descriptorpb.FileDescriptorProto) {
+	if _, err := r.files.FindFileByPath(fd.GetName()); err == nil {
+		return
+	}
+
+	for _, dep := range fd.GetDependency() {
+		if _, err := r.files.FindFileByPath(dep); err == nil {
+			continue
+		}
+
+		if wk, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+			_ = r.files.RegisterFile(wk)
+			continue
+		}
+
+		if depFd, ok := known[dep]; ok {
+			r.register(depFd, known)
+		}
+	}
+
+	// Options like gogoproto ones are declared in files the nodes may not
+	// serve, and aren't needed to decode messages.
+	f, err := protodesc.FileOptions{AllowUnresolvable: true}.New(fd, r.files)
+	if err != nil {
+		return
+	}
+
+	_ = r.files.RegisterFile(f)
+}
+
+// staticFile returns the descriptor of the file declaring the gogoproto
+// message type t.
+func staticFile(t reflect.Type) (*descriptorpb.FileDescriptorProto, error) {
+	msg := reflect.New(t.Elem()).Interface()
+
+	d, ok := msg.(interface{ Descriptor() ([]byte, []int) })
+	if !ok {
+		return nil, fmt.Errorf("%T has no descriptor", msg)
+	}
+
+	gz, _ := d.Descriptor()
+
+	return unzipFile(gz)
+}
+
+// staticServiceFile returns the descriptor of the compiled in file declaring
+// the service named name. The gogoproto registry is keyed by path, the files
+// are looked up where the SDK and its modules declare their services.
+func staticServiceFile(name protoreflect.FullName) (*descriptorpb.FileDescriptorProto, bool) {
+	dir := strings.ReplaceAll(string(name.Parent()), ".", "/")
+
+	for _, file := range []string{"query.proto", "tx.proto", "service.proto", strings.ToLower(string(name.Name())) + ".proto"} {
+		gz := gogoproto.FileDescriptor(dir + "/" + file)
+		if gz == nil {
+			continue
+		}
+
+		fd, err := unzipFile(gz)
+		if err != nil {
+			continue
+		}
+
+		for _, sd := range fd.GetService() {
+			if protoreflect.FullName(fd.GetPackage()).Append(protoreflect.Name(sd.GetName())) == name {
+				return fd, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// unzipFile decodes a gzipped file descriptor, as registered by gogoproto.
+func unzipFile(gz []byte) (*descriptorpb.FileDescriptorProto, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file descriptor, %w", err)
+	}
+
+	b, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file descriptor, %w", err)
+	}
+
+	fd := &descriptorpb.FileDescriptorProto{}
+	if err := proto.Unmarshal(b, fd); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal file descriptor, %w", err)
+	}
+
+	return fd, nil
+}
+
+// reflectionFiles sends req to the reflection service of the nodes behind
+// grpcConn, returning the file descriptors it answers with.
+func reflectionFiles(ctx context.Context, grpcConn grpc.ClientConnInterface, req *rpb.ServerReflectionRequest) ([]*descriptorpb.FileDescriptorProto, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := rpb.NewServerReflectionClient(grpcConn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, queryError("reflection", err)
+	}
+
+	if err := stream.Send(req); err != nil {
+		return nil, queryError("reflection", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, queryError("reflection", err)
+	}
+
+	_ = stream.CloseSend()
+
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, newError(KindNotFound, fmt.Errorf("reflection error: %s", e.GetErrorMessage()))
+	}
+
+	ret := []*descriptorpb.FileDescriptorProto{}
+	for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fd := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(b, fd); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal file descriptor from reflection, %w", err)
+		}
+
+		ret = append(ret, fd)
+	}
+
+	return ret, nil
+}
+
+// chainTypes resolves the message types of a chain for protojson, which
+// calls it for the content of Any fields.
+type chainTypes struct {
+	ctx      context.Context
+	conn     grpc.ClientConnInterface
+	resolver *descriptorResolver
+}
+
+func (t chainTypes) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	md, err := t.resolver.find(t.ctx, t.conn, name)
+	if err != nil {
+		return nil, protoregistry.NotFound
+	}
+
+	return dynamicpb.NewMessageType(md), nil
+}
+
+func (t chainTypes) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	name := url
+	if idx := strings.LastIndex(url, "/"); idx >= 0 {
+		name = url[idx+1:]
+	}
+
+	return t.FindMessageByName(protoreflect.FullName(name))
+}
+
+func (t chainTypes) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	return nil, protoregistry.NotFound
+}
+
+func (t chainTypes) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	return nil, protoregistry.NotFound
+}
+
+// marshalJSON encodes msg as proto-JSON, using the static codec if it knows
+// all the types msg holds, and the descriptors of the chain otherwise.
+func (q *chainQuerier) marshalJSON(ctx context.Context, msg gogoproto.Message) ([]byte, error) {
+	ret, err := q.cdc.MarshalJSON(msg)
+	if err == nil {
+		return ret, nil
+	}
+
+	pm, ok := msg.(codec.ProtoMarshaler)
+	if !ok {
+		return nil, err
+	}
+
+	bz, err := pm.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal %T, %w", msg, err)
+	}
+
+	types := chainTypes{
+		ctx:      ctx,
+		conn:     q.conn,
+		resolver: q.types,
+	}
+
+	mt, err := types.FindMessageByName(protoreflect.FullName(gogoproto.MessageName(msg)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve type of %T, %w", msg, err)
+	}
+
+	dyn := mt.New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: types}).Unmarshal(bz, dyn); err != nil {
+		return nil, fmt.Errorf("cannot decode %T, %w", msg, err)
+	}
+
+	ret, err = protojson.MarshalOptions{
+		Resolver:        types,
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+	}.Marshal(dyn)
+	if err != nil {
+		return nil, fmt.Errorf("cannot json marshal %T, %w", msg, err)
+	}
+
+	// protojson output is deliberately unstable, compact it so that
+	// responses don't depend on the decoding path.
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, ret); err != nil {
+		return nil, fmt.Errorf("cannot compact json of %T, %w", msg, err)
+	}
+
+	return compact.Bytes(), nil
+}
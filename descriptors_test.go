@@ -0,0 +1,251 @@
+package sdkservice
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// testFiles declares test.A and test.C, both holding a test.B declared in
+// another file, and the test.S service querying bank balances.
+var testFiles = map[string]*descriptorpb.FileDescriptorProto{
+	"test/s.proto": {
+		Name:       proto.String("test/s.proto"),
+		Package:    proto.String("test"),
+		Dependency: []string{"cosmos/bank/v1beta1/query.proto"},
+		Syntax:     proto.String("proto3"),
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("S"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{
+					Name:       proto.String("Get"),
+					InputType:  proto.String(".cosmos.bank.v1beta1.QueryBalanceRequest"),
+					OutputType: proto.String(".cosmos.bank.v1beta1.QueryBalanceResponse"),
+				},
+				{
+					Name:            proto.String("Watch"),
+					InputType:       proto.String(".cosmos.bank.v1beta1.QueryBalanceRequest"),
+					OutputType:      proto.String(".cosmos.bank.v1beta1.QueryBalanceResponse"),
+					ServerStreaming: proto.Bool(true),
+				},
+			},
+		}},
+	},
+	"test/a.proto": testFileWithB("A"),
+	"test/c.proto": testFileWithB("C"),
+	"test/b.proto": {
+		Name:    proto.String("test/b.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("B"),
+		}},
+	},
+}
+
+func testFileWithB(message string) *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/" + strings.ToLower(message) + ".proto"),
+		Package:    proto.String("test"),
+		Dependency: []string{"test/b.proto"},
+		Syntax:     proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String(message),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("b"),
+				JsonName: proto.String("b"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+				TypeName: proto.String(".test.B"),
+			}},
+		}},
+	}
+}
+
+// fakeReflection serves testFiles through the gRPC reflection service.
+// Requests of a file by name fail with the errors queued in fail, and
+// requests by symbol are signaled on waiting then wait for unblock to be
+// closed.
+type fakeReflection struct {
+	mu      sync.Mutex
+	fail    map[string][]error
+	calls   map[string]int
+	waiting chan struct{}
+	unblock chan struct{}
+}
+
+func (f *fakeReflection) ServerReflectionInfo(stream rpb.ServerReflection_ServerReflectionInfoServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	var name string
+	switch r := req.MessageRequest.(type) {
+	case *rpb.ServerReflectionRequest_FileByFilename:
+		name = r.FileByFilename
+	case *rpb.ServerReflectionRequest_FileContainingSymbol:
+		if f.unblock != nil {
+			f.waiting <- struct{}{}
+			<-f.unblock
+		}
+
+		name = "test/" + strings.ToLower(strings.TrimPrefix(r.FileContainingSymbol, "test.")) + ".proto"
+	default:
+		return status.Errorf(codes.Unimplemented, "unexpected request %T", r)
+	}
+
+	f.mu.Lock()
+	f.calls[name]++
+	var failure error
+	if errs := f.fail[name]; len(errs) > 0 {
+		failure, f.fail[name] = errs[0], errs[1:]
+	}
+	f.mu.Unlock()
+
+	if failure != nil {
+		if failure == errFileNotFound {
+			return stream.Send(&rpb.ServerReflectionResponse{
+				MessageResponse: &rpb.ServerReflectionResponse_ErrorResponse{
+					ErrorResponse: &rpb.ErrorResponse{ErrorCode: int32(codes.NotFound), ErrorMessage: "file not found"},
+				},
+			})
+		}
+
+		return failure
+	}
+
+	fd, ok := testFiles[name]
+	if !ok {
+		return status.Errorf(codes.NotFound, "unknown file %s", name)
+	}
+
+	b, err := proto.Marshal(fd)
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&rpb.ServerReflectionResponse{
+		MessageResponse: &rpb.ServerReflectionResponse_FileDescriptorResponse{
+			FileDescriptorResponse: &rpb.FileDescriptorResponse{FileDescriptorProto: [][]byte{b}},
+		},
+	})
+}
+
+// errFileNotFound makes fakeReflection answer with an error response.
+var errFileNotFound = status.Error(codes.NotFound, "file not found")
+
+func (f *fakeReflection) called(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls[name]
+}
+
+func newReflectionConn(t *testing.T, f *fakeReflection) *grpc.ClientConn {
+	f.calls = map[string]int{}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer()
+	rpb.RegisterServerReflectionServer(s, f)
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// fieldB returns the type of the b field of md.
+func fieldB(md protoreflect.MessageDescriptor) protoreflect.MessageDescriptor {
+	return md.Fields().ByName("b").Message()
+}
+
+func TestDescriptorResolverTransientFailure(t *testing.T) {
+	f := &fakeReflection{
+		fail: map[string][]error{
+			"test/b.proto": {status.Error(codes.Unavailable, "node restarting")},
+		},
+	}
+	conn := newReflectionConn(t, f)
+	r := newDescriptorResolver()
+
+	md, err := r.find(context.Background(), conn, "test.A")
+	require.NoError(t, err)
+	require.True(t, fieldB(md).IsPlaceholder())
+
+	// The file failing to be fetched is asked for again.
+	md, err = r.find(context.Background(), conn, "test.C")
+	require.NoError(t, err)
+	require.False(t, fieldB(md).IsPlaceholder())
+	require.Equal(t, protoreflect.FullName("test.B"), fieldB(md).FullName())
+	require.Equal(t, 2, f.called("test/b.proto"))
+}
+
+func TestDescriptorResolverMissingFile(t *testing.T) {
+	for name, failure := range map[string]error{
+		"error response": errFileNotFound,
+		"not found":      status.Error(codes.NotFound, "unknown file"),
+	} {
+		f := &fakeReflection{
+			fail: map[string][]error{"test/b.proto": {failure}},
+		}
+		conn := newReflectionConn(t, f)
+		r := newDescriptorResolver()
+
+		md, err := r.find(context.Background(), conn, "test.A")
+		require.NoError(t, err, name)
+		require.True(t, fieldB(md).IsPlaceholder(), name)
+
+		// Files the nodes don't have aren't asked for again.
+		md, err = r.find(context.Background(), conn, "test.C")
+		require.NoError(t, err, name)
+		require.True(t, fieldB(md).IsPlaceholder(), name)
+		require.Equal(t, 1, f.called("test/b.proto"), name)
+	}
+}
+
+func TestDescriptorResolverConcurrentFind(t *testing.T) {
+	f := &fakeReflection{waiting: make(chan struct{}), unblock: make(chan struct{})}
+	conn := newReflectionConn(t, f)
+	r := newDescriptorResolver()
+
+	done := make(chan error)
+	go func() {
+		_, err := r.find(context.Background(), conn, "test.A")
+		done <- err
+	}()
+	<-f.waiting
+
+	// Types compiled in the service are resolved while another lookup
+	// waits for the nodes.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	md, err := r.find(ctx, conn, "cosmos.bank.v1beta1.MsgSend")
+	require.NoError(t, err)
+	require.Equal(t, protoreflect.FullName("cosmos.bank.v1beta1.MsgSend"), md.FullName())
+
+	close(f.unblock)
+	require.NoError(t, <-done)
+}
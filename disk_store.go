@@ -0,0 +1,165 @@
+package sdkservice
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	entriesBucket = []byte("entries")
+	orderBucket   = []byte("order")
+	metaBucket    = []byte("meta")
+	sizeKey       = []byte("size")
+	countKey      = []byte("count")
+)
+
+// diskStore is a size-bounded key-value store kept in a bbolt file, which
+// evicts its oldest entries first.
+// Entries are held in the entries bucket, the order bucket maps an
+// increasing sequence number to each key in insertion order.
+type diskStore struct {
+	db       *bolt.DB
+	maxBytes int64
+}
+
+func openDiskStore(path string, maxBytes int64) (*diskStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("cannot open cache store %s, %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{entriesBucket, orderBucket, metaBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot initialize cache store %s, %w", path, err)
+	}
+
+	return &diskStore{
+		db:       db,
+		maxBytes: maxBytes,
+	}, nil
+}
+
+// get returns the value held for key, or nil if there's none.
+func (d *diskStore) get(key string) ([]byte, error) {
+	var ret []byte
+	err := d.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(entriesBucket).Get([]byte(key))
+		if v != nil {
+			// v is only valid during the transaction.
+			ret = append([]byte(nil), v...)
+		}
+
+		return nil
+	})
+
+	return ret, err
+}
+
+// put stores value for key, and returns the number of entries evicted to
+// make room for it.
+func (d *diskStore) put(key string, value []byte) (int, error) {
+	entrySize := int64(len(key) + len(value))
+	if entrySize > d.maxBytes {
+		return 0, nil
+	}
+
+	var evicted int
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+		order := tx.Bucket(orderBucket)
+		meta := tx.Bucket(metaBucket)
+
+		if entries.Get([]byte(key)) != nil {
+			return nil
+		}
+
+		seq, err := order.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		if err := order.Put(uint64Bytes(seq), []byte(key)); err != nil {
+			return err
+		}
+
+		if err := entries.Put([]byte(key), value); err != nil {
+			return err
+		}
+
+		size := int64(bytesUint64(meta.Get(sizeKey))) + entrySize
+
+		c := order.Cursor()
+		for k, oldKey := c.First(); k != nil && size > d.maxBytes; k, oldKey = c.First() {
+			size -= int64(len(oldKey) + len(entries.Get(oldKey)))
+
+			if err := entries.Delete(oldKey); err != nil {
+				return err
+			}
+
+			if err := c.Delete(); err != nil {
+				return err
+			}
+
+			evicted++
+		}
+
+		count := bytesUint64(meta.Get(countKey)) + 1 - uint64(evicted)
+		if err := meta.Put(countKey, uint64Bytes(count)); err != nil {
+			return err
+		}
+
+		return meta.Put(sizeKey, uint64Bytes(uint64(size)))
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("cannot store %s in cache store, %w", key, err)
+	}
+
+	return evicted, nil
+}
+
+// stats returns the number of entries held and their size.
+func (d *diskStore) stats() (int, int64, error) {
+	var entries int
+	var size int64
+	err := d.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		entries = int(bytesUint64(meta.Get(countKey)))
+		size = int64(bytesUint64(meta.Get(sizeKey)))
+
+		return nil
+	})
+
+	return entries, size, err
+}
+
+func (d *diskStore) close() error {
+	return d.db.Close()
+}
+
+func uint64Bytes(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+
+	return b
+}
+
+func bytesUint64(b []byte) uint64 {
+	if len(b) != 8 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(b)
+}
@@ -0,0 +1,254 @@
+package sdkservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	goapb "goa.design/goa/v3/grpc/pb"
+	goa "goa.design/goa/v3/pkg"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrModuleNotPresent is returned when querying a module which the chain
+// doesn't run, as opposed to a module failing to answer. Such errors are
+// classified as KindModuleNotPresent, which maps to the Unimplemented gRPC
+// code and the 501 HTTP status code.
+var ErrModuleNotPresent = errors.New("module not present on chain")
+
+// ErrorKind classifies the errors returned by the service, so that clients
+// can tell them apart.
+type ErrorKind string
+
+// Error kinds returned by the service.
+const (
+	KindNotFound            ErrorKind = "not_found"
+	KindInvalidArgument     ErrorKind = "invalid_argument"
+	KindUpstreamUnavailable ErrorKind = "upstream_unavailable"
+	KindUpstreamTimeout     ErrorKind = "upstream_timeout"
+	KindChainMismatch       ErrorKind = "chain_mismatch"
+	KindUnsupportedChain    ErrorKind = "unsupported_chain"
+	KindTxRejected          ErrorKind = "tx_rejected"
+	KindModuleNotPresent    ErrorKind = "module_not_present"
+	KindForbidden           ErrorKind = "forbidden"
+	KindCanceled            ErrorKind = "canceled"
+	KindInternal            ErrorKind = "internal"
+)
+
+var kindCodes = map[ErrorKind]struct {
+	grpc codes.Code
+	http int
+}{
+	KindNotFound:            {codes.NotFound, http.StatusNotFound},
+	KindInvalidArgument:     {codes.InvalidArgument, http.StatusBadRequest},
+	KindUpstreamUnavailable: {codes.Unavailable, http.StatusBadGateway},
+	KindUpstreamTimeout:     {codes.DeadlineExceeded, http.StatusGatewayTimeout},
+	KindChainMismatch:       {codes.FailedPrecondition, http.StatusMisdirectedRequest},
+	KindUnsupportedChain:    {codes.NotFound, http.StatusNotFound},
+	KindTxRejected:          {codes.FailedPrecondition, http.StatusUnprocessableEntity},
+	KindModuleNotPresent:    {codes.Unimplemented, http.StatusNotImplemented},
+	KindForbidden:           {codes.PermissionDenied, http.StatusForbidden},
+	KindCanceled:            {codes.Canceled, 499},
+	KindInternal:            {codes.Internal, http.StatusInternalServerError},
+}
+
+// sdkCodes classifies the errors of the "sdk" codespace, as defined in
+// cosmos-sdk types/errors. Errors of other codespaces are only returned by
+// transactions, and are classified as KindTxRejected.
+var sdkCodes = map[uint32]ErrorKind{
+	2:  KindInvalidArgument, // tx parse error
+	6:  KindInvalidArgument, // unknown request
+	7:  KindInvalidArgument, // invalid address
+	8:  KindInvalidArgument, // invalid pubkey
+	9:  KindNotFound,        // unknown address
+	10: KindInvalidArgument, // invalid coins
+	18: KindInvalidArgument, // invalid request
+	21: KindInvalidArgument, // tx too large
+	22: KindNotFound,        // key not found
+	38: KindNotFound,        // not found
+}
+
+// Error is an error returned by the service, classified by Kind.
+// Codespace and Code are set when the error comes from an SDK module, and
+// Reason when it's a failed transaction.
+type Error struct {
+	Kind      ErrorKind
+	Codespace string
+	Code      uint32
+	Reason    FailureReason
+	Err       error
+}
+
+func newError(kind ErrorKind, err error) *Error {
+	return &Error{
+		Kind: kind,
+		Err:  err,
+	}
+}
+
+// newSDKError returns the Error matching an SDK error, identified by its
+// codespace and code.
+func newSDKError(codespace string, code uint32, err error) *Error {
+	kind := KindTxRejected
+	if codespace == "sdk" {
+		if k, ok := sdkCodes[code]; ok {
+			kind = k
+		}
+	}
+
+	return &Error{
+		Kind:      kind,
+		Codespace: codespace,
+		Code:      code,
+		Err:       err,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus returns the gRPC status matching e, whose first detail holds
+// the error kind.
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(kindCodes[e.Kind].grpc, e.Error())
+
+	withDetails, err := st.WithDetails(&goapb.ErrorResponse{
+		Name:      string(e.Kind),
+		Msg:       e.Error(),
+		Timeout:   e.Kind == KindUpstreamTimeout,
+		Temporary: e.temporary(),
+		Fault:     e.Kind == KindInternal,
+	})
+	if err != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// temporary tells whether the same call may succeed later.
+func (e *Error) temporary() bool {
+	return e.Kind == KindUpstreamUnavailable || e.Kind == KindUpstreamTimeout
+}
+
+// StatusCode returns the HTTP status code matching e.
+func (e *Error) StatusCode() int {
+	return kindCodes[e.Kind].http
+}
+
+// MarshalJSON encodes e as the body of an HTTP error response.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kind      ErrorKind     `json:"kind"`
+		Message   string        `json:"message"`
+		Codespace string        `json:"codespace,omitempty"`
+		Code      uint32        `json:"code,omitempty"`
+		Reason    FailureReason `json:"reason,omitempty"`
+	}{
+		Kind:      e.Kind,
+		Message:   e.Error(),
+		Codespace: e.Codespace,
+		Code:      e.Code,
+		Reason:    e.Reason,
+	})
+}
+
+// ClassifyError returns err as an Error, classifying it from the sentinel
+// errors of this package and the gRPC status returned by the upstream.
+func ClassifyError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	switch {
+	case errors.Is(err, ErrModuleNotPresent):
+		return newError(KindModuleNotPresent, err)
+	case errors.Is(err, ErrChainNotConfigured):
+		return newError(KindUnsupportedChain, err)
+	case errors.Is(err, ErrChainMismatch):
+		return newError(KindChainMismatch, err)
+	case errors.Is(err, errNoNodes):
+		return newError(KindUpstreamUnavailable, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return newError(KindUpstreamTimeout, err)
+	case errors.Is(err, context.Canceled):
+		return newError(KindCanceled, err)
+	}
+
+	switch grpcCode(err) {
+	case codes.NotFound:
+		return newError(KindNotFound, err)
+	case codes.InvalidArgument, codes.OutOfRange:
+		return newError(KindInvalidArgument, err)
+	case codes.Unavailable:
+		return newError(KindUpstreamUnavailable, err)
+	case codes.DeadlineExceeded:
+		return newError(KindUpstreamTimeout, err)
+	case codes.Canceled:
+		return newError(KindCanceled, err)
+	case codes.Unimplemented:
+		return newError(KindModuleNotPresent, err)
+	default:
+		return newError(KindInternal, err)
+	}
+}
+
+// ClassifyErrors is an endpoint middleware which classifies the errors
+// returned by the service, so that both transports encode them with the
+// right status code.
+func ClassifyErrors(e goa.Endpoint) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		res, err := e(ctx, req)
+		if err != nil {
+			return nil, ClassifyError(err)
+		}
+
+		return res, nil
+	}
+}
+
+// queryError returns the error of a failed query to module, telling chains
+// which don't run module apart from other failures. Errors which are already
+// classified are returned as is.
+func queryError(module string, err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	if grpcCode(err) == codes.Unimplemented {
+		return newError(KindModuleNotPresent, fmt.Errorf("%w: %s, %s", ErrModuleNotPresent, module, err))
+	}
+
+	return fmt.Errorf("cannot query %s, %w", module, err)
+}
+
+// moduleNotPresent returns the error of a query to module on a chain which
+// doesn't run it.
+func moduleNotPresent(module string) error {
+	return newError(KindModuleNotPresent, fmt.Errorf("%w: %s", ErrModuleNotPresent, module))
+}
+
+// grpcCode returns the gRPC status code of err, looking through wrapped
+// errors which status.FromError doesn't unwrap.
+func grpcCode(err error) codes.Code {
+	var se interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &se) {
+		return se.GRPCStatus().Code()
+	}
+
+	return codes.Unknown
+}
@@ -0,0 +1,159 @@
+package sdkservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	goagrpc "goa.design/goa/v3/grpc"
+	goapb "goa.design/goa/v3/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// classifyTests are errors as returned by the queries, wrapped along the
+// way, and their classification.
+var classifyTests = []struct {
+	name string
+	err  error
+	kind ErrorKind
+	grpc codes.Code
+	http int
+}{
+	{
+		name: "node not found",
+		err:  fmt.Errorf("chain test: %w", status.Error(codes.NotFound, "account not found")),
+		kind: KindNotFound,
+		grpc: codes.NotFound,
+		http: http.StatusNotFound,
+	},
+	{
+		name: "invalid request",
+		err:  queryError("bank", status.Error(codes.InvalidArgument, "invalid denom")),
+		kind: KindInvalidArgument,
+		grpc: codes.InvalidArgument,
+		http: http.StatusBadRequest,
+	},
+	{
+		name: "height out of range",
+		err:  queryError("bank", status.Error(codes.OutOfRange, "height too high")),
+		kind: KindInvalidArgument,
+		grpc: codes.InvalidArgument,
+		http: http.StatusBadRequest,
+	},
+	{
+		name: "node unavailable",
+		err:  queryError("bank", fmt.Errorf("chain test: %w", status.Error(codes.Unavailable, "connection refused"))),
+		kind: KindUpstreamUnavailable,
+		grpc: codes.Unavailable,
+		http: http.StatusBadGateway,
+	},
+	{
+		name: "no usable node",
+		err:  queryError("bank", fmt.Errorf("chain test: %w", errNoNodes)),
+		kind: KindUpstreamUnavailable,
+		grpc: codes.Unavailable,
+		http: http.StatusBadGateway,
+	},
+	{
+		name: "node timeout",
+		err:  queryError("bank", status.Error(codes.DeadlineExceeded, "deadline exceeded")),
+		kind: KindUpstreamTimeout,
+		grpc: codes.DeadlineExceeded,
+		http: http.StatusGatewayTimeout,
+	},
+	{
+		name: "context deadline",
+		err:  fmt.Errorf("cannot query bank, %w", context.DeadlineExceeded),
+		kind: KindUpstreamTimeout,
+		grpc: codes.DeadlineExceeded,
+		http: http.StatusGatewayTimeout,
+	},
+	{
+		name: "canceled",
+		err:  queryError("bank", status.Error(codes.Canceled, "context canceled")),
+		kind: KindCanceled,
+		grpc: codes.Canceled,
+		http: 499,
+	},
+	{
+		name: "module not present",
+		err:  fmt.Errorf("cannot get params, %w", queryError("liquidity", status.Error(codes.Unimplemented, "unknown service"))),
+		kind: KindModuleNotPresent,
+		grpc: codes.Unimplemented,
+		http: http.StatusNotImplemented,
+	},
+	{
+		name: "chain not configured",
+		err:  fmt.Errorf("chain osmosis: %w", ErrChainNotConfigured),
+		kind: KindUnsupportedChain,
+		grpc: codes.NotFound,
+		http: http.StatusNotFound,
+	},
+	{
+		name: "classified error",
+		err:  fmt.Errorf("cannot track, %w", newError(KindForbidden, errors.New("tracking disabled"))),
+		kind: KindForbidden,
+		grpc: codes.PermissionDenied,
+		http: http.StatusForbidden,
+	},
+	{
+		name: "sdk error",
+		err:  fmt.Errorf("cannot broadcast, %w", newSDKError("sdk", 5, errors.New("insufficient funds"))),
+		kind: KindTxRejected,
+		grpc: codes.FailedPrecondition,
+		http: http.StatusUnprocessableEntity,
+	},
+	{
+		name: "unknown status",
+		err:  queryError("bank", status.Error(codes.Unknown, "panic")),
+		kind: KindInternal,
+		grpc: codes.Internal,
+		http: http.StatusInternalServerError,
+	},
+	{
+		name: "not a status",
+		err:  errors.New("cannot unmarshal response"),
+		kind: KindInternal,
+		grpc: codes.Internal,
+		http: http.StatusInternalServerError,
+	},
+}
+
+func TestClassifyError(t *testing.T) {
+	require.Nil(t, ClassifyError(nil))
+
+	for _, tt := range classifyTests {
+		e := ClassifyError(tt.err)
+		require.Equal(t, tt.kind, e.Kind, tt.name)
+		require.Equal(t, tt.http, e.StatusCode(), tt.name)
+		// Errors classified deeper in the chain lose the context wrapping
+		// them.
+		require.Contains(t, tt.err.Error(), e.Error(), tt.name)
+
+		// The gRPC transport encodes errors through goa.
+		st := status.Convert(goagrpc.EncodeError(e))
+		require.Equal(t, tt.grpc, st.Code(), tt.name)
+		require.Equal(t, e.Error(), st.Message(), tt.name)
+
+		details := st.Details()
+		require.NotEmpty(t, details, tt.name)
+
+		detail, ok := details[0].(*goapb.ErrorResponse)
+		require.True(t, ok, tt.name)
+		require.Equal(t, string(tt.kind), detail.Name, tt.name)
+		require.Equal(t, tt.kind == KindUpstreamUnavailable || tt.kind == KindUpstreamTimeout, detail.Temporary, tt.name)
+	}
+}
+
+func TestGRPCCode(t *testing.T) {
+	require.Equal(t, codes.Unknown, grpcCode(errors.New("plain")))
+	require.Equal(t, codes.NotFound, grpcCode(fmt.Errorf("a: %w", fmt.Errorf("b: %w", status.Error(codes.NotFound, "c")))))
+	require.Equal(t, codes.Unimplemented, grpcCode(moduleNotPresent("liquidity")))
+
+	// Classified errors keep their kind when wrapped.
+	require.Equal(t, codes.Unavailable, grpcCode(fmt.Errorf("a: %w", newError(KindUpstreamUnavailable, errors.New("b")))))
+}
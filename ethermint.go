@@ -0,0 +1,399 @@
+package sdkservice
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	auth "github.com/cosmos/cosmos-sdk/x/auth/types"
+	gogoproto "github.com/gogo/protobuf/proto"
+	"golang.org/x/crypto/sha3"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The account and key types of Ethermint based chains, like Evmos, Cronos
+// or Kava, are declared here with the same wire format as the Ethermint
+// ones rather than importing Ethermint, which would bring go-ethereum and an
+// incompatible cosmos-sdk fork in.
+
+// ethSecp256k1Type is the name of the eth_secp256k1 key type.
+const ethSecp256k1Type = "eth_secp256k1"
+
+func init() {
+	gogoproto.RegisterType((*EthAccount)(nil), "ethermint.types.v1.EthAccount")
+	gogoproto.RegisterType((*EthSecp256k1PubKey)(nil), "ethermint.crypto.v1.ethsecp256k1.PubKey")
+	gogoproto.RegisterType((*MsgEthereumTx)(nil), "ethermint.evm.v1.MsgEthereumTx")
+}
+
+// registerEthermint registers the Ethermint account, key and message types.
+func registerEthermint(registry codectypes.InterfaceRegistry) {
+	registry.RegisterImplementations((*auth.AccountI)(nil), &EthAccount{})
+	registry.RegisterImplementations((*auth.GenesisAccount)(nil), &EthAccount{})
+	registry.RegisterImplementations((*cryptotypes.PubKey)(nil), &EthSecp256k1PubKey{})
+	registry.RegisterImplementations((*sdktypes.Msg)(nil), &MsgEthereumTx{})
+}
+
+// EthAccount is the account of Ethermint based chains, a base account with
+// the hash of the code of the EVM contract it holds.
+type EthAccount struct {
+	BaseAccount *auth.BaseAccount `protobuf:"bytes,1,opt,name=base_account,json=baseAccount,proto3" json:"base_account,omitempty"`
+	CodeHash    string            `protobuf:"bytes,2,opt,name=code_hash,json=codeHash,proto3" json:"code_hash,omitempty"`
+}
+
+var (
+	_ auth.AccountI                      = (*EthAccount)(nil)
+	_ auth.GenesisAccount                = (*EthAccount)(nil)
+	_ codectypes.UnpackInterfacesMessage = (*EthAccount)(nil)
+)
+
+func (a *EthAccount) Reset()         { *a = EthAccount{} }
+func (a *EthAccount) String() string { return gogoproto.CompactTextString(a) }
+func (*EthAccount) ProtoMessage()    {}
+
+func (*EthAccount) Descriptor() ([]byte, []int) {
+	return ethermintFiles[ethermintAccountFile], []int{0}
+}
+
+func (a *EthAccount) base() *auth.BaseAccount {
+	if a.BaseAccount == nil {
+		a.BaseAccount = &auth.BaseAccount{}
+	}
+
+	return a.BaseAccount
+}
+
+func (a *EthAccount) GetAddress() sdktypes.AccAddress { return a.base().GetAddress() }
+
+func (a *EthAccount) SetAddress(addr sdktypes.AccAddress) error { return a.base().SetAddress(addr) }
+
+func (a *EthAccount) GetPubKey() cryptotypes.PubKey { return a.base().GetPubKey() }
+
+func (a *EthAccount) SetPubKey(pk cryptotypes.PubKey) error { return a.base().SetPubKey(pk) }
+
+func (a *EthAccount) GetAccountNumber() uint64 { return a.base().GetAccountNumber() }
+
+func (a *EthAccount) SetAccountNumber(n uint64) error { return a.base().SetAccountNumber(n) }
+
+func (a *EthAccount) GetSequence() uint64 { return a.base().GetSequence() }
+
+func (a *EthAccount) SetSequence(seq uint64) error { return a.base().SetSequence(seq) }
+
+func (a *EthAccount) Validate() error { return a.base().Validate() }
+
+// UnpackInterfaces unpacks the public key of the base account.
+func (a *EthAccount) UnpackInterfaces(unpacker codectypes.AnyUnpacker) error {
+	return a.base().UnpackInterfaces(unpacker)
+}
+
+// The encoding methods below replace the reflection based ones of gogoproto,
+// which can't encode the Any of the public key of the base account.
+
+func (a *EthAccount) Marshal() ([]byte, error) {
+	var b []byte
+
+	if a.BaseAccount != nil {
+		base, err := a.BaseAccount.Marshal()
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, base)
+	}
+
+	if a.CodeHash != "" {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, a.CodeHash)
+	}
+
+	return b, nil
+}
+
+func (a *EthAccount) MarshalTo(dAtA []byte) (int, error) {
+	return marshalTo(a, dAtA)
+}
+
+func (a *EthAccount) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	return marshalToSizedBuffer(a, dAtA)
+}
+
+func (a *EthAccount) Size() int {
+	b, _ := a.Marshal()
+	return len(b)
+}
+
+func (a *EthAccount) Unmarshal(dAtA []byte) error {
+	base, err := protoField(dAtA, 1)
+	if err != nil {
+		return err
+	}
+
+	codeHash, err := protoField(dAtA, 2)
+	if err != nil {
+		return err
+	}
+
+	*a = EthAccount{
+		BaseAccount: &auth.BaseAccount{},
+		CodeHash:    string(codeHash),
+	}
+
+	return a.BaseAccount.Unmarshal(base)
+}
+
+// EthSecp256k1PubKey is a compressed secp256k1 public key, whose address is
+// derived like Ethereum ones.
+type EthSecp256k1PubKey struct {
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+var _ cryptotypes.PubKey = (*EthSecp256k1PubKey)(nil)
+
+func (pk *EthSecp256k1PubKey) Reset()         { *pk = EthSecp256k1PubKey{} }
+func (pk *EthSecp256k1PubKey) String() string { return fmt.Sprintf("EthPubKeySecp256k1{%X}", pk.Key) }
+func (*EthSecp256k1PubKey) ProtoMessage()     {}
+
+func (*EthSecp256k1PubKey) Descriptor() ([]byte, []int) {
+	return ethermintFiles[ethermintKeysFile], []int{0}
+}
+
+func (pk *EthSecp256k1PubKey) Marshal() ([]byte, error) {
+	if len(pk.Key) == 0 {
+		return nil, nil
+	}
+
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendBytes(b, pk.Key), nil
+}
+
+func (pk *EthSecp256k1PubKey) MarshalTo(dAtA []byte) (int, error) {
+	return marshalTo(pk, dAtA)
+}
+
+func (pk *EthSecp256k1PubKey) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	return marshalToSizedBuffer(pk, dAtA)
+}
+
+func (pk *EthSecp256k1PubKey) Size() int {
+	b, _ := pk.Marshal()
+	return len(b)
+}
+
+func (pk *EthSecp256k1PubKey) Unmarshal(dAtA []byte) error {
+	key, err := protoField(dAtA, 1)
+	if err != nil {
+		return err
+	}
+
+	*pk = EthSecp256k1PubKey{Key: append([]byte(nil), key...)}
+
+	return nil
+}
+
+// Address returns the last 20 bytes of the keccak256 hash of the
+// uncompressed key, or nil if the key is invalid.
+func (pk *EthSecp256k1PubKey) Address() cryptotypes.Address {
+	key, err := btcec.ParsePubKey(pk.Key, btcec.S256())
+	if err != nil {
+		return nil
+	}
+
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(key.SerializeUncompressed()[1:])
+
+	return cryptotypes.Address(h.Sum(nil)[12:])
+}
+
+func (pk *EthSecp256k1PubKey) Bytes() []byte {
+	return pk.Key
+}
+
+// VerifySignature verifies the [R || S || V] or [R || S] signature of the
+// keccak256 hash of msg, rejecting malleable signatures.
+func (pk *EthSecp256k1PubKey) VerifySignature(msg []byte, sig []byte) bool {
+	if len(sig) == 65 {
+		sig = sig[:64]
+	}
+
+	if len(sig) != 64 {
+		return false
+	}
+
+	key, err := btcec.ParsePubKey(pk.Key, btcec.S256())
+	if err != nil {
+		return false
+	}
+
+	signature := &btcec.Signature{
+		R: new(big.Int).SetBytes(sig[:32]),
+		S: new(big.Int).SetBytes(sig[32:]),
+	}
+
+	if signature.S.Cmp(new(big.Int).Rsh(btcec.S256().N, 1)) > 0 {
+		return false
+	}
+
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(msg)
+
+	return signature.Verify(h.Sum(nil), key)
+}
+
+func (pk *EthSecp256k1PubKey) Equals(other cryptotypes.PubKey) bool {
+	return pk.Type() == other.Type() && bytes.Equal(pk.Bytes(), other.Bytes())
+}
+
+func (pk *EthSecp256k1PubKey) Type() string {
+	return ethSecp256k1Type
+}
+
+// MsgEthereumTx is an Ethereum transaction sent to an Ethermint based chain.
+// Data packs the legacy, access list or dynamic fee transaction, which are
+// left packed and decoded from their descriptors. The deprecated size field
+// is dropped.
+type MsgEthereumTx struct {
+	Data *codectypes.Any `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Hash string          `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	// From is the hex address of the sender.
+	From string `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+}
+
+var _ sdktypes.Msg = (*MsgEthereumTx)(nil)
+
+func (m *MsgEthereumTx) Reset()         { *m = MsgEthereumTx{} }
+func (m *MsgEthereumTx) String() string { return gogoproto.CompactTextString(m) }
+func (*MsgEthereumTx) ProtoMessage()    {}
+
+func (*MsgEthereumTx) Descriptor() ([]byte, []int) {
+	return ethermintFiles[ethermintTxFile], []int{0}
+}
+
+// ValidateBasic checks that m holds a transaction and that its sender, if
+// set, is a hex address.
+func (m *MsgEthereumTx) ValidateBasic() error {
+	if m.Data == nil {
+		return fmt.Errorf("ethereum transaction has no data")
+	}
+
+	if m.From != "" && m.sender() == nil {
+		return fmt.Errorf("invalid sender address %q", m.From)
+	}
+
+	return nil
+}
+
+// GetSigners returns the sender of m, if set.
+func (m *MsgEthereumTx) GetSigners() []sdktypes.AccAddress {
+	if sender := m.sender(); sender != nil {
+		return []sdktypes.AccAddress{sender}
+	}
+
+	return nil
+}
+
+func (m *MsgEthereumTx) sender() sdktypes.AccAddress {
+	addr, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(m.From), "0x"))
+	if err != nil || len(addr) != 20 {
+		return nil
+	}
+
+	return addr
+}
+
+func (m *MsgEthereumTx) Marshal() ([]byte, error) {
+	var b []byte
+
+	if m.Data != nil {
+		data, err := m.Data.Marshal()
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, data)
+	}
+
+	for _, f := range []struct {
+		num   protowire.Number
+		value string
+	}{{3, m.Hash}, {4, m.From}} {
+		if f.value != "" {
+			b = protowire.AppendTag(b, f.num, protowire.BytesType)
+			b = protowire.AppendString(b, f.value)
+		}
+	}
+
+	return b, nil
+}
+
+func (m *MsgEthereumTx) MarshalTo(dAtA []byte) (int, error) {
+	return marshalTo(m, dAtA)
+}
+
+func (m *MsgEthereumTx) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	return marshalToSizedBuffer(m, dAtA)
+}
+
+func (m *MsgEthereumTx) Size() int {
+	b, _ := m.Marshal()
+	return len(b)
+}
+
+func (m *MsgEthereumTx) Unmarshal(dAtA []byte) error {
+	fields := map[protowire.Number][]byte{}
+	for _, num := range []protowire.Number{1, 3, 4} {
+		v, err := protoField(dAtA, num)
+		if err != nil {
+			return err
+		}
+
+		fields[num] = v
+	}
+
+	*m = MsgEthereumTx{
+		Hash: string(fields[3]),
+		From: string(fields[4]),
+	}
+
+	if fields[1] == nil {
+		return nil
+	}
+
+	m.Data = &codectypes.Any{}
+
+	return m.Data.Unmarshal(fields[1])
+}
+
+// marshalTo writes the encoding of m at the start of dAtA.
+func marshalTo(m interface{ Marshal() ([]byte, error) }, dAtA []byte) (int, error) {
+	b, err := m.Marshal()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(b) > len(dAtA) {
+		return 0, fmt.Errorf("buffer too small to marshal %T", m)
+	}
+
+	return copy(dAtA, b), nil
+}
+
+// marshalToSizedBuffer writes the encoding of m at the end of dAtA.
+func marshalToSizedBuffer(m interface{ Marshal() ([]byte, error) }, dAtA []byte) (int, error) {
+	b, err := m.Marshal()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(b) > len(dAtA) {
+		return 0, fmt.Errorf("buffer too small to marshal %T", m)
+	}
+
+	return copy(dAtA[len(dAtA)-len(b):], b), nil
+}
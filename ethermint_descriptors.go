@@ -0,0 +1,192 @@
+package sdkservice
+
+import (
+	"bytes"
+	"compress/gzip"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// The descriptors of the files declaring the Ethermint types, registered like
+// gogoproto generated files do, so that they are resolved like the other
+// types compiled in the service. Only the declarations are kept, not the
+// gogoproto and cosmos_proto options.
+
+const (
+	ethermintAccountFile = "ethermint/types/v1/account.proto"
+	ethermintKeysFile    = "ethermint/crypto/v1/ethsecp256k1/keys.proto"
+	ethermintTxFile      = "ethermint/evm/v1/tx.proto"
+)
+
+// ethermintFiles holds the gzipped descriptors of the Ethermint files.
+var ethermintFiles = map[string][]byte{}
+
+func init() {
+	for _, fd := range []*descriptorpb.FileDescriptorProto{
+		{
+			Name:       proto.String(ethermintAccountFile),
+			Package:    proto.String("ethermint.types.v1"),
+			Dependency: []string{"cosmos/auth/v1beta1/auth.proto"},
+			Syntax:     proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				descriptorMessage("EthAccount",
+					messageField("base_account", 1, ".cosmos.auth.v1beta1.BaseAccount"),
+					scalarField("code_hash", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				),
+			},
+		},
+		{
+			Name:    proto.String(ethermintKeysFile),
+			Package: proto.String("ethermint.crypto.v1.ethsecp256k1"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				descriptorMessage("PubKey", scalarField("key", 1, descriptorpb.FieldDescriptorProto_TYPE_BYTES)),
+				descriptorMessage("PrivKey", scalarField("key", 1, descriptorpb.FieldDescriptorProto_TYPE_BYTES)),
+			},
+		},
+		ethermintTxDescriptor(),
+	} {
+		gz, err := gzipFile(fd)
+		if err != nil {
+			panic(err)
+		}
+
+		ethermintFiles[fd.GetName()] = gz
+		gogoproto.RegisterFile(fd.GetName(), gz)
+	}
+}
+
+// ethermintTxDescriptor returns the descriptor of the file declaring the
+// Ethereum transaction message and the transaction data it packs. The access
+// lists and logs are declared in evm.proto, fetched from the nodes.
+func ethermintTxDescriptor() *descriptorpb.FileDescriptorProto {
+	const (
+		str    = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		bz     = descriptorpb.FieldDescriptorProto_TYPE_BYTES
+		u64    = descriptorpb.FieldDescriptorProto_TYPE_UINT64
+		double = descriptorpb.FieldDescriptorProto_TYPE_DOUBLE
+	)
+
+	accesses := func(number int32) *descriptorpb.FieldDescriptorProto {
+		f := messageField("accesses", number, ".ethermint.evm.v1.AccessTuple")
+		f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		return f
+	}
+
+	logs := messageField("logs", 2, ".ethermint.evm.v1.Log")
+	logs.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String(ethermintTxFile),
+		Package:    proto.String("ethermint.evm.v1"),
+		Dependency: []string{"google/protobuf/any.proto", "ethermint/evm/v1/evm.proto"},
+		Syntax:     proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			descriptorMessage("MsgEthereumTx",
+				messageField("data", 1, ".google.protobuf.Any"),
+				scalarField("size", 2, double),
+				scalarField("hash", 3, str),
+				scalarField("from", 4, str),
+			),
+			descriptorMessage("LegacyTx",
+				scalarField("nonce", 1, u64),
+				scalarField("gas_price", 2, str),
+				scalarField("gas", 3, u64),
+				scalarField("to", 4, str),
+				scalarField("value", 5, str),
+				scalarField("data", 6, bz),
+				scalarField("v", 7, bz),
+				scalarField("r", 8, bz),
+				scalarField("s", 9, bz),
+			),
+			descriptorMessage("AccessListTx",
+				scalarField("chain_id", 1, str),
+				scalarField("nonce", 2, u64),
+				scalarField("gas_price", 3, str),
+				scalarField("gas", 4, u64),
+				scalarField("to", 5, str),
+				scalarField("value", 6, str),
+				scalarField("data", 7, bz),
+				accesses(8),
+				scalarField("v", 9, bz),
+				scalarField("r", 10, bz),
+				scalarField("s", 11, bz),
+			),
+			descriptorMessage("DynamicFeeTx",
+				scalarField("chain_id", 1, str),
+				scalarField("nonce", 2, u64),
+				scalarField("gas_tip_cap", 3, str),
+				scalarField("gas_fee_cap", 4, str),
+				scalarField("gas", 5, u64),
+				scalarField("to", 6, str),
+				scalarField("value", 7, str),
+				scalarField("data", 8, bz),
+				accesses(9),
+				scalarField("v", 10, bz),
+				scalarField("r", 11, bz),
+				scalarField("s", 12, bz),
+			),
+			descriptorMessage("ExtensionOptionsEthereumTx"),
+			descriptorMessage("MsgEthereumTxResponse",
+				scalarField("hash", 1, str),
+				logs,
+				scalarField("ret", 3, bz),
+				scalarField("vm_error", 4, str),
+				scalarField("gas_used", 5, u64),
+			),
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Msg"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("EthereumTx"),
+				InputType:  proto.String(".ethermint.evm.v1.MsgEthereumTx"),
+				OutputType: proto.String(".ethermint.evm.v1.MsgEthereumTxResponse"),
+			}},
+		}},
+	}
+}
+
+func descriptorMessage(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+	return &descriptorpb.DescriptorProto{
+		Name:  proto.String(name),
+		Field: fields,
+	}
+}
+
+func scalarField(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   typ.Enum(),
+	}
+}
+
+func messageField(name string, number int32, typeName string) *descriptorpb.FieldDescriptorProto {
+	f := scalarField(name, number, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE)
+	f.TypeName = proto.String(typeName)
+
+	return f
+}
+
+// gzipFile encodes fd as gogoproto registers file descriptors.
+func gzipFile(fd *descriptorpb.FileDescriptorProto) ([]byte, error) {
+	b, err := proto.Marshal(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,129 @@
+package sdkservice
+
+import (
+	"context"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	auth "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// generatorKey is the compressed secp256k1 generator point, whose Ethereum
+// address is a well-known one.
+var generatorKey = []byte{
+	0x02, 0x79, 0xbe, 0x66, 0x7e, 0xf9, 0xdc, 0xbb, 0xac, 0x55, 0xa0, 0x62, 0x95, 0xce, 0x87, 0x0b, 0x07,
+	0x02, 0x9b, 0xfc, 0xdb, 0x2d, 0xce, 0x28, 0xd9, 0x59, 0xf2, 0x81, 0x5b, 0x16, 0xf8, 0x17, 0x98,
+}
+
+const generatorAddress = "7e5f4552091a69125d5dfcb7b8c2659029395bdf"
+
+func ethermintQuerier(conn *fakeConn) *chainQuerier {
+	q := testQuerier("evmos", SDKv44, conn)
+	q.chain.CodecExtensions = []string{ExtensionEthermint}
+	q.cdc = extendedCodec(q.sdk, q.chain.CodecExtensions)
+
+	return q
+}
+
+// legacyTx returns the encoding of an Ethermint LegacyTx.
+func legacyTx(nonce uint64, gasPrice string) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, nonce)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	return protowire.AppendString(b, gasPrice)
+}
+
+func TestEthermintAccount(t *testing.T) {
+	pk, err := codectypes.NewAnyWithValue(&EthSecp256k1PubKey{Key: generatorKey})
+	require.NoError(t, err)
+
+	account, err := codectypes.NewAnyWithValue(&EthAccount{
+		BaseAccount: &auth.BaseAccount{AccountNumber: 4, Sequence: 2, PubKey: pk},
+		CodeHash:    "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+	})
+	require.NoError(t, err)
+
+	q := ethermintQuerier(newFakeConn(map[string]interface{}{
+		"/cosmos.auth.v1beta1.Query/Account": &auth.QueryAccountResponse{Account: account},
+	}))
+
+	details, err := q.AccountDetails(context.Background(), AccountAddress{Hex: generatorAddress, Bech32Prefix: "evmos"})
+	require.NoError(t, err)
+	require.Equal(t, "/ethermint.types.v1.EthAccount", details.Type)
+	require.Equal(t, uint64(4), details.AccountNumber)
+	require.Equal(t, uint64(2), details.Sequence)
+	require.Equal(t, "/ethermint.crypto.v1.ethsecp256k1.PubKey", details.PublicKey.Type)
+	require.Equal(t, generatorKey, details.PublicKey.Key)
+
+	accountI, err := q.account(context.Background(), details.Address)
+	require.NoError(t, err)
+	require.Equal(t, generatorAddress, hex.EncodeToString(accountI.GetPubKey().Address()))
+}
+
+func TestEthermintTx(t *testing.T) {
+	msg := &MsgEthereumTx{
+		Data: &codectypes.Any{TypeUrl: "/ethermint.evm.v1.LegacyTx", Value: legacyTx(7, "1000")},
+		Hash: "0xabcd",
+		From: "0x" + generatorAddress,
+	}
+	require.NoError(t, msg.ValidateBasic())
+	require.Equal(t, generatorAddress, hex.EncodeToString(msg.GetSigners()[0]))
+
+	txBytes := testTx(t, []*codectypes.Any{testAny(t, msg)})
+	q := ethermintQuerier(newFakeConn(nil))
+
+	summary, err := summarizeTx(q.cdc, txBytes)
+	require.NoError(t, err)
+	require.Len(t, summary.Messages, 1)
+	require.Equal(t, "/ethermint.evm.v1.MsgEthereumTx", summary.Messages[0].Type)
+	require.Equal(t, "0x"+generatorAddress, summary.Messages[0].Sender)
+
+	// TxMetadata doesn't know the chain, extension messages are only listed.
+	meta, err := txMetadata(sdkAdapters[defaultSDKVersion].Codec(), txBytes)
+	require.NoError(t, err)
+	require.Len(t, meta.MessagesMetadata, 1)
+	require.Equal(t, "/ethermint.evm.v1.MsgEthereumTx", meta.MessagesMetadata[0].MsgType)
+
+	// The transaction data isn't compiled in the service, and is decoded
+	// from the descriptor of its file.
+	var tx sdktx.Tx
+	require.NoError(t, q.cdc.Unmarshal(txBytes, &tx))
+
+	ret, err := q.marshalJSON(context.Background(), &sdktx.GetTxResponse{Tx: &tx})
+	require.NoError(t, err)
+	require.Contains(t, string(ret), `"@type":"/ethermint.evm.v1.LegacyTx"`)
+	require.Contains(t, string(ret), `"gas_price":"1000"`)
+	require.Contains(t, string(ret), `"from":"0x`+generatorAddress+`"`)
+
+	require.Error(t, (&MsgEthereumTx{Data: msg.Data, From: "0x1234"}).ValidateBasic())
+	require.Error(t, (&MsgEthereumTx{From: msg.From}).ValidateBasic())
+}
+
+func TestEthermintStaticFiles(t *testing.T) {
+	r := newDescriptorResolver()
+	conn := newFakeConn(nil)
+
+	for name, msg := range map[protoreflect.FullName]interface{}{
+		"ethermint.types.v1.EthAccount":           &EthAccount{},
+		"ethermint.crypto.v1.ethsecp256k1.PubKey": &EthSecp256k1PubKey{},
+		"ethermint.evm.v1.MsgEthereumTx":          &MsgEthereumTx{},
+	} {
+		fd, err := staticFile(reflect.TypeOf(msg))
+		require.NoError(t, err, name)
+
+		md, err := r.find(context.Background(), conn, name)
+		require.NoError(t, err, name)
+		require.Equal(t, fd.GetName(), md.ParentFile().Path(), name)
+	}
+
+	md, err := r.find(context.Background(), conn, "ethermint.evm.v1.DynamicFeeTx")
+	require.NoError(t, err)
+	require.NotNil(t, md.Fields().ByName("gas_fee_cap"))
+}
@@ -0,0 +1,86 @@
+package sdkservice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeReply computes the reply of a call from its request.
+type fakeReply func(args interface{}) (interface{}, error)
+
+// fakeConn answers upstream calls with canned replies, recording the methods
+// called. Replies are messages, raw []byte, errors returned as is, or
+// fakeReply. Calls to other methods fail as Unimplemented.
+type fakeConn struct {
+	mu      sync.Mutex
+	replies map[string]interface{}
+	calls   map[string]int
+}
+
+func newFakeConn(replies map[string]interface{}) *fakeConn {
+	return &fakeConn{
+		replies: replies,
+		calls:   map[string]int{},
+	}
+}
+
+func (c *fakeConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	c.mu.Lock()
+	c.calls[method]++
+	r, ok := c.replies[method]
+	c.mu.Unlock()
+
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+	}
+
+	if f, ok := r.(fakeReply); ok {
+		var err error
+		if r, err = f(args); err != nil {
+			return err
+		}
+	}
+
+	var bz []byte
+	switch r := r.(type) {
+	case error:
+		return r
+	case []byte:
+		bz = r
+	case codec.ProtoMarshaler:
+		var err error
+		if bz, err = r.Marshal(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unexpected reply %T", r)
+	}
+
+	switch out := reply.(type) {
+	case *[]byte:
+		*out = append([]byte{}, bz...)
+		return nil
+	case codec.ProtoMarshaler:
+		return out.Unmarshal(bz)
+	default:
+		return status.Errorf(codes.Internal, "unexpected reply type %T", reply)
+	}
+}
+
+func (c *fakeConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "unknown method %s", method)
+}
+
+// called returns the number of calls to method.
+func (c *fakeConn) called(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls[method]
+}
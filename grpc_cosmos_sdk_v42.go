@@ -1,745 +1,263 @@
-//go:build sdk_v42
-// +build sdk_v42
-
 package sdkservice
 
 import (
 	"context"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
-	"sync"
-
-	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"time"
 
-	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 	"github.com/cosmos/cosmos-sdk/codec"
-	"github.com/cosmos/cosmos-sdk/types/bech32"
-	emoneyinflation "github.com/e-money/em-ledger/x/inflation/types"
-	liquidity "github.com/gravity-devs/liquidity/x/liquidity/types"
-	"github.com/tendermint/tendermint/abci/types"
-
-	mint "github.com/cosmos/cosmos-sdk/x/mint/types"
-
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
-	auth "github.com/cosmos/cosmos-sdk/x/auth/types"
 	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
-	distribution "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	sdkutilities "github.com/emerishq/sdk-service-meta/gen/sdk_utilities"
-
-	gaia "github.com/cosmos/gaia/v3/app"
+	gogotypes "github.com/gogo/protobuf/types"
 	"google.golang.org/grpc"
-)
-
-var (
-	grpcPort                 = 9090
-	cdc      codec.Marshaler = nil
-	cdcOnce  sync.Once
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
 const (
-	// TODO : this can be used used once relvant code was uncommented
-	// transferMsgType = "transfer"
-
 	emoneyChainName = "emoney"
-)
-
-func initCodec() {
-	c, _ := gaia.MakeCodecs()
-	cdc = c
-}
-
-func getCodec() codec.Marshaler {
-	cdcOnce.Do(initCodec)
-
-	return cdc
-}
-
-func QuerySupply(ctx context.Context, chainName string, port *int, paginationKey *string) (sdkutilities.Supply2, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.Supply2{}, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	bankQuery := bank.NewQueryClient(grpcConn)
-
-	suppRes, err := bankQuery.TotalSupply(ctx, &bank.QueryTotalSupplyRequest{})
-	if err != nil {
-		return sdkutilities.Supply2{}, err
-	}
-
-	ret := sdkutilities.Supply2{}
-
-	ret.Pagination = &sdkutilities.Pagination{}
-
-	for _, s := range suppRes.Supply {
-		ret.Coins = append(ret.Coins, &sdkutilities.Coin{
-			Denom:  s.Denom,
-			Amount: s.Amount.String(),
-		})
-	}
-
-	return ret, nil
-}
-
-func SupplyDenom(ctx context.Context, chainName string, port *int, denom *string) (*sdkutilities.Supply2, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return &sdkutilities.Supply2{}, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
 
-	bankQuery := bank.NewQueryClient(grpcConn)
-	suppRes, err := bankQuery.SupplyOf(ctx, &bank.QuerySupplyOfRequest{Denom: *denom})
-	if err != nil {
-		return &sdkutilities.Supply2{}, err
-	}
+	emoneyInflationMethod = "/em.inflation.v1.Query/Inflation"
+)
 
-	ret := sdkutilities.Supply2{Coins: []*sdkutilities.Coin{{Denom: *denom, Amount: suppRes.Amount.String()}}}
+// sdkV42 implements the upstream queries of cosmos-sdk v0.40 to v0.42.
+type sdkV42 struct{}
 
-	return &ret, nil
+func (sdkV42) Codec() codec.Codec {
+	return getCodec()
 }
 
-func GetTxFromHash(ctx context.Context, chainName string, port *int, hash string) ([]byte, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-
-	grpcConn, err := grpc.Dial(
-		fmt.Sprintf("%s:%d", chainName, *port),
-		grpc.WithInsecure(),
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	txClient := sdktx.NewServiceClient(grpcConn)
-
-	grpcRes, err := txClient.GetTx(ctx, &sdktx.GetTxRequest{Hash: hash})
-	if err != nil {
-		return nil, err
-	}
-
-	return getCodec().MarshalJSON(grpcRes)
+func (sdkV42) NewInterfaceRegistry() codectypes.InterfaceRegistry {
+	return newInterfaceRegistry()
 }
 
-func BroadcastTx(ctx context.Context, chainName string, port *int, txBytes []byte) (string, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-
-	grpcConn, err := grpc.Dial(
-		fmt.Sprintf("%s:%d", chainName, *port), // Or your gRPC server address.
-		grpc.WithInsecure(),                    // The SDK doesn't support any transport security mechanism.
-	)
-
-	if err != nil {
-		return "", fmt.Errorf("cannot create grpc dialer, %w", err)
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	txClient := sdktx.NewServiceClient(grpcConn)
-	// We then call the BroadcastTx method on this client.
-	grpcRes, err := txClient.BroadcastTx(
-		ctx,
-		&sdktx.BroadcastTxRequest{
-			Mode:    sdktx.BroadcastMode_BROADCAST_MODE_SYNC,
-			TxBytes: txBytes, // Proto-binary of the signed transaction, see previous step.
-		},
-	)
-
-	if err != nil {
-		return "", err
-	}
-
-	if grpcRes.TxResponse.Code != types.CodeTypeOK {
-		return "", fmt.Errorf("transaction relaying error: code %d, %s", grpcRes.TxResponse.Code, grpcRes.TxResponse.RawLog)
-	}
-
-	return grpcRes.TxResponse.TxHash, nil
+// TotalSupply returns the whole supply, which isn't paginated before v0.43.
+func (sdkV42) TotalSupply(ctx context.Context, grpcConn grpc.ClientConnInterface, pageKey []byte) (*bank.QueryTotalSupplyResponse, error) {
+	return bank.NewQueryClient(grpcConn).TotalSupply(ctx, &bank.QueryTotalSupplyRequest{})
 }
 
-func TxMetadata(ctx context.Context, txBytes []byte) (sdkutilities.TxMessagesMetadata, error) {
-	txObj := sdktx.Tx{}
-
-	if err := getCodec().UnmarshalBinaryBare(txBytes, &txObj); err != nil {
-		return sdkutilities.TxMessagesMetadata{}, fmt.Errorf("cannot unmarshal transaction, %w", err)
-	}
-
-	ret := sdkutilities.TxMessagesMetadata{}
-
-	// Don't include ibc-go momentarily even though v42 isn't affected,
-	// for consistency reasons.
-	// TODO: reintroduce once terra fixes their stuff
-	/*for idx, m := range txObj.GetMsgs() {
-		txm := sdkutilities.MsgMetadata{}
-		txm.MsgType = m.Type()
-
-		switch m.Type() {
-		case transferMsgType:
-			mt, ok := m.(*ibcTypes.MsgTransfer)
-			if !ok {
-				return sdkutilities.TxMessagesMetadata{}, fmt.Errorf("transaction message %d: expected MsgTransfer, got %T", idx, m)
-			}
-
-			it := sdkutilities.IBCTransferMetadata{
-				SourcePort:    &mt.SourcePort,
-				SourceChannel: &mt.SourceChannel,
-				Token: &sdkutilities.Coin{
-					Denom:  mt.Token.Denom,
-					Amount: mt.Token.Amount.String(),
-				},
-				Sender:   &mt.Sender,
-				Receiver: &mt.Receiver,
-				TimeoutHeight: &sdkutilities.IBCHeight{
-					RevisionNumber: &mt.TimeoutHeight.RevisionNumber,
-					RevisionHeight: &mt.TimeoutHeight.RevisionHeight,
-				},
-				TiemoutTimestamp: &mt.TimeoutTimestamp,
-			}
-
-			txm.IbcTransferMetadata = &it
-		}
-	}*/
-
-	return ret, nil
-}
-
-func Block(ctx context.Context, chainName string, port *int, height int64) (sdkutilities.BlockData, error) {
-	if port == nil {
-		port = &grpcPort
-	}
+// Simulate sends the decoded transaction, simulating raw transaction bytes
+// isn't supported before v0.43.
+func (sdkV42) Simulate(ctx context.Context, grpcConn grpc.ClientConnInterface, txBytes []byte) (*sdktx.SimulateResponse, error) {
+	txObj := &sdktx.Tx{}
 
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.BlockData{}, err
+	if err := getCodec().Unmarshal(txBytes, txObj); err != nil {
+		return nil, newError(KindInvalidArgument, fmt.Errorf("cannot unmarshal transaction, %w", err))
 	}
 
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	sc := tmservice.NewServiceClient(grpcConn)
-	resp, err := sc.GetBlockByHeight(ctx, &tmservice.GetBlockByHeightRequest{
-		Height: height,
+	return sdktx.NewServiceClient(grpcConn).Simulate(ctx, &sdktx.SimulateRequest{
+		Tx: txObj, //nolint:staticcheck
 	})
-
-	if err != nil {
-		return sdkutilities.BlockData{}, err
-	}
-
-	ret := sdkutilities.BlockData{}
-
-	respJSON, err := json.Marshal(resp)
-	if err != nil {
-		return sdkutilities.BlockData{}, fmt.Errorf("cannot json marshal response from block height, %w", err)
-	}
-
-	ret.Height = height
-	ret.Block = respJSON
-
-	return ret, nil
 }
 
-func LiquidityParams(ctx context.Context, chainName string, port *int) (sdkutilities.LiquidityParams2, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.LiquidityParams2{}, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	lq := liquidity.NewQueryClient(grpcConn)
-
-	resp, err := lq.Params(ctx, &liquidity.QueryParamsRequest{})
-
-	if err != nil {
-		return sdkutilities.LiquidityParams2{}, nil
-	}
-
-	respJSON, err := json.Marshal(resp)
-	if err != nil {
-		return sdkutilities.LiquidityParams2{}, fmt.Errorf("cannot json marshal response from liquidity params, %w", err)
-	}
-
-	ret := sdkutilities.LiquidityParams2{
-		LiquidityParams: respJSON,
-	}
-
-	return ret, nil
+// TxNotFound also matches the Tendermint RPC error returned as is by nodes
+// before v0.44, which don't set the NotFound code.
+func (sdkV42) TxNotFound(err error) bool {
+	return isTxNotFound(err) || (grpcCode(err) == codes.Unknown && strings.Contains(err.Error(), "not found"))
 }
 
-func LiquidityPools(ctx context.Context, chainName string, port *int) (sdkutilities.LiquidityPools2, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.LiquidityPools2{}, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	lq := liquidity.NewQueryClient(grpcConn)
-
-	resp, err := lq.LiquidityPools(ctx, &liquidity.QueryLiquidityPoolsRequest{})
-
-	if err != nil {
-		return sdkutilities.LiquidityPools2{}, nil
-	}
-
-	respJSON, err := json.Marshal(resp)
-	if err != nil {
-		return sdkutilities.LiquidityPools2{}, fmt.Errorf("cannot json marshal response from liquidity pools, %w", err)
-	}
-
-	ret := sdkutilities.LiquidityPools2{
-		LiquidityPools: respJSON,
-	}
-
-	return ret, nil
+var v42ChainHooks = map[string]chainHooks{
+	// emoney inflation is different from the traditional cosmos sdk inflation,
+	// and does not have params nor an annualprovisions endpoint. Instead it
+	// uses a flat inflation rate per asset, provided by its inflation module.
+	emoneyChainName: {
+		MintInflation:       emoneyInflation,
+		MintParams:          notPresent("mint"),
+		MintAnnualProvision: notPresent("mint"),
+		EmoneyInflation:     emoneyState,
+	},
 }
 
-func MintInflation(ctx context.Context, chainName string, port *int) (sdkutilities.MintInflation2, error) {
-	if chainName == emoneyChainName {
-		// emoney inflation is different from the traditional cosmos sdk inflation,
-		// and does not have an annualprovisions endpoint. Instead it uses a flat inflation
-		// rate provided in the endpoint.
-		return emoneyInflation(ctx, chainName, port)
-	}
-
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.MintInflation2{}, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	mq := mint.NewQueryClient(grpcConn)
-
-	resp, err := mq.Inflation(ctx, &mint.QueryInflationRequest{})
-
-	if err != nil {
-		return sdkutilities.MintInflation2{}, err
-	}
-
-	respJSON, err := json.Marshal(resp)
-	if err != nil {
-		return sdkutilities.MintInflation2{}, fmt.Errorf("cannot json marshal response from mint inflation, %w", err)
-	}
-
-	ret := sdkutilities.MintInflation2{
-		MintInflation: respJSON,
-	}
-
-	return ret, nil
+// Hooks returns the custom mint modules of chainName. None of the optional
+// modules are available to v0.42 chains.
+func (sdkV42) Hooks(chainName string) chainHooks {
+	return v42ChainHooks[strings.ToLower(chainName)]
 }
 
-func MintParams(ctx context.Context, chainName string, port *int) (sdkutilities.MintParams2, error) {
-	if chainName == emoneyChainName {
-		// emoney inflation is different from the traditional cosmos sdk inflation,
-		// and does not have an annualprovisions endpoint. Instead it uses a flat inflation
-		// rate provided in the endpoint.
-		return sdkutilities.MintParams2{}, nil
-	}
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.MintParams2{}, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	mq := mint.NewQueryClient(grpcConn)
-
-	resp, err := mq.Params(ctx, &mint.QueryParamsRequest{})
-
-	if err != nil {
-		return sdkutilities.MintParams2{}, err
-	}
-
-	respJSON, err := json.Marshal(resp)
-	if err != nil {
-		return sdkutilities.MintParams2{}, fmt.Errorf("cannot json marshal response from mint params, %w", err)
-	}
-
-	ret := sdkutilities.MintParams2{
-		MintParams: respJSON,
+// notPresent answers that the chain doesn't run module, without querying the
+// upstream.
+func notPresent(module string) moduleQuery {
+	return func(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+		return nil, moduleNotPresent(module)
 	}
-
-	return ret, nil
 }
 
-func MintAnnualProvision(ctx context.Context, chainName string, port *int) (sdkutilities.MintAnnualProvision2, error) {
-	if chainName == emoneyChainName {
-		// emoney inflation is different from the traditional cosmos sdk inflation,
-		// and does not have an annualprovisions endpoint. Instead it uses a flat inflation
-		// rate provided in the endpoint.
-		return sdkutilities.MintAnnualProvision2{}, nil
-	}
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
+// emoneyInflation returns the inflation of ungm from the inflation module of
+// e-money.
+func emoneyInflation(ctx context.Context, grpcConn grpc.ClientConnInterface) (interface{}, error) {
+	state, err := emoneyState(ctx, grpcConn)
 	if err != nil {
-		return sdkutilities.MintAnnualProvision2{}, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	mq := mint.NewQueryClient(grpcConn)
-
-	resp, err := mq.AnnualProvisions(ctx, &mint.QueryAnnualProvisionsRequest{})
-
-	if err != nil {
-		return sdkutilities.MintAnnualProvision2{}, err
-	}
-
-	respJSON, err := json.Marshal(resp)
-	if err != nil {
-		return sdkutilities.MintAnnualProvision2{}, fmt.Errorf("cannot json marshal response from mint annual provision, %w", err)
+		return nil, err
 	}
 
-	ret := sdkutilities.MintAnnualProvision2{
-		MintAnnualProvision: respJSON,
+	for _, a := range state.Assets {
+		if a.Denom == "ungm" {
+			return json.RawMessage(fmt.Sprintf("{\"inflation\":\"%s\"}", a.Inflation)), nil
+		}
 	}
 
-	return ret, nil
-}
-
-func MintEpochProvisions(ctx context.Context, chainName string, port *int) (sdkutilities.MintEpochProvisions2, error) {
-	return sdkutilities.MintEpochProvisions2{
-		MintEpochProvisions: nil,
-	}, nil
+	return nil, newError(KindNotFound, fmt.Errorf("no inflation for ungm in emoney inflation state"))
 }
 
-func AccountNumbers(ctx context.Context, chainName string, port *int, hexAddress string, bech32hrp string) (sdkutilities.AccountNumbers2, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.AccountNumbers2{}, err
+// emoneyState queries the inflation module of e-money, whose types aren't
+// compiled in the service, and decodes its state from the raw response.
+func emoneyState(ctx context.Context, grpcConn grpc.ClientConnInterface) (*sdkutilities.EmoneyState, error) {
+	var req, resp []byte
+	if err := grpcConn.Invoke(ctx, emoneyInflationMethod, &req, &resp, grpc.ForceCodec(rawCodec{})); err != nil {
+		return nil, err
 	}
 
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	addrBytes, err := hex.DecodeString(hexAddress)
+	// QueryInflationResponse.state
+	state, err := protoField(resp, 1)
 	if err != nil {
-		return sdkutilities.AccountNumbers2{}, err
+		return nil, fmt.Errorf("cannot decode emoney inflation, %w", err)
 	}
 
-	addr, err := bech32.ConvertAndEncode(bech32hrp, addrBytes)
-	if err != nil {
-		return sdkutilities.AccountNumbers2{}, err
+	ret := &sdkutilities.EmoneyState{
+		Assets: []*sdkutilities.EmoneyAsset{},
 	}
 
-	authQuery := auth.NewQueryClient(grpcConn)
-
-	res, err := authQuery.Account(ctx, &auth.QueryAccountRequest{
-		Address: addr,
-	})
-
+	// InflationState.last_applied
+	lastApplied, err := protoField(state, 1)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			return sdkutilities.AccountNumbers2{}, nil
-		}
-
-		return sdkutilities.AccountNumbers2{}, err
+		return nil, fmt.Errorf("cannot decode emoney inflation, %w", err)
 	}
 
-	ret := sdkutilities.AccountNumbers2{}
-
-	if res == nil {
-		return ret, fmt.Errorf("account has no numbers associated")
-	}
+	if lastApplied != nil {
+		var ts gogotypes.Timestamp
+		if err := ts.Unmarshal(lastApplied); err != nil {
+			return nil, fmt.Errorf("cannot decode emoney inflation last applied time, %w", err)
+		}
 
-	// get a baseAccount
-	var accountI auth.AccountI
+		t, err := gogotypes.TimestampFromProto(&ts)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode emoney inflation last applied time, %w", err)
+		}
 
-	if err := getCodec().UnpackAny(res.Account, &accountI); err != nil {
-		return sdkutilities.AccountNumbers2{}, err
+		ret.LastApplied = t.UTC().Format(time.RFC3339Nano)
 	}
 
-	ret.AccountNumber = int64(accountI.GetAccountNumber())
-	ret.SequenceNumber = int64(accountI.GetSequence())
-	ret.Bech32Address = addr
-
-	return ret, nil
-}
-
-func DelegatorRewards(ctx context.Context, chainName string, port *int, hexAddress string, bech32hrp string) (sdkutilities.DelegatorRewards2, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
+	// InflationState.last_applied_height
+	height, err := protoField(state, 2)
 	if err != nil {
-		return sdkutilities.DelegatorRewards2{}, err
+		return nil, fmt.Errorf("cannot decode emoney inflation, %w", err)
 	}
 
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	addrBytes, err := hex.DecodeString(hexAddress)
-	if err != nil {
-		return sdkutilities.DelegatorRewards2{}, err
-	}
+	if height != nil {
+		var h sdktypes.Int
+		if err := h.Unmarshal(height); err != nil {
+			return nil, fmt.Errorf("cannot decode emoney inflation last applied height, %w", err)
+		}
 
-	addr, err := bech32.ConvertAndEncode(bech32hrp, addrBytes)
-	if err != nil {
-		return sdkutilities.DelegatorRewards2{}, err
+		ret.LastAppliedHeight = h.String()
 	}
 
-	distributionQuery := distribution.NewQueryClient(grpcConn)
-
-	res, err := distributionQuery.DelegationTotalRewards(ctx, &distribution.QueryDelegationTotalRewardsRequest{
-		DelegatorAddress: addr,
-	})
-
+	// InflationState.assets
+	assets, err := protoFields(state, 3)
 	if err != nil {
-		return sdkutilities.DelegatorRewards2{}, err
+		return nil, fmt.Errorf("cannot decode emoney inflation, %w", err)
 	}
 
-	ret := sdkutilities.DelegatorRewards2{}
-
-	for _, d := range res.Rewards {
-		r := &sdkutilities.DelegationDelegatorReward{
-			ValidatorAddress: d.ValidatorAddress,
-		}
-
-		for _, rr := range d.Reward {
-			r.Rewards = append(r.Rewards, sdkDecCoinToUtilCoin(rr))
+	for _, a := range assets {
+		asset, err := decodeEmoneyAsset(a)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode emoney inflation asset, %w", err)
 		}
 
-		ret.Rewards = append(ret.Rewards, r)
-	}
-
-	for _, d := range res.Total {
-		ret.Total = append(ret.Total, sdkDecCoinToUtilCoin(d))
+		ret.Assets = append(ret.Assets, asset)
 	}
 
 	return ret, nil
 }
 
-func FeeEstimate(ctx context.Context, chainName string, port *int, txBytes []byte) (sdkutilities.Simulation, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
+func decodeEmoneyAsset(b []byte) (*sdkutilities.EmoneyAsset, error) {
+	// InflationAsset.denom
+	denom, err := protoField(b, 1)
 	if err != nil {
-		return sdkutilities.Simulation{}, err
+		return nil, err
 	}
 
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	txObj := &sdktx.Tx{}
-
-	if err := getCodec().UnmarshalBinaryBare(txBytes, txObj); err != nil {
-		return sdkutilities.Simulation{}, fmt.Errorf("cannot unmarshal transaction, %w", err)
+	// InflationAsset.inflation
+	inflation, err := protoDec(b, 2)
+	if err != nil {
+		return nil, err
 	}
 
-	txSvcClient := sdktx.NewServiceClient(grpcConn)
-	simRes, err := txSvcClient.Simulate(ctx, &sdktx.SimulateRequest{
-		Tx: txObj,
-	})
+	// InflationAsset.accum
+	accum, err := protoDec(b, 3)
 	if err != nil {
-		return sdkutilities.Simulation{}, err
+		return nil, err
 	}
 
-	return sdkutilities.Simulation{
-		GasWanted: simRes.GasInfo.GasWanted,
-		GasUsed:   simRes.GasInfo.GasUsed,
+	return &sdkutilities.EmoneyAsset{
+		Denom:     string(denom),
+		Inflation: inflation,
+		Accum:     accum,
 	}, nil
-
 }
 
-func sdkDecCoinToUtilCoin(c sdktypes.DecCoin) *sdkutilities.Coin {
-	return &sdkutilities.Coin{
-		Denom:  c.Denom,
-		Amount: c.Amount.String(),
-	}
-}
-
-func StakingParams(ctx context.Context, chainName string, port *int) (sdkutilities.StakingParams2, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.StakingParams2{}, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	sq := staking.NewQueryClient(grpcConn)
-	resp, err := sq.Params(ctx, &staking.QueryParamsRequest{})
-	if err != nil {
-		return sdkutilities.StakingParams2{}, nil
+// protoDec returns the string form of the sdk.Dec field num of the protobuf
+// message b, or an empty string if it's not set.
+func protoDec(b []byte, num protowire.Number) (string, error) {
+	v, err := protoField(b, num)
+	if err != nil || v == nil {
+		return "", err
 	}
 
-	respJSON, err := json.Marshal(resp)
-	if err != nil {
-		return sdkutilities.StakingParams2{}, fmt.Errorf("cannot json marshal response from staking params, %w", err)
+	var d sdktypes.Dec
+	if err := d.Unmarshal(v); err != nil {
+		return "", err
 	}
 
-	return sdkutilities.StakingParams2{
-		StakingParams: respJSON,
-	}, nil
+	return d.String(), nil
 }
 
-func StakingPool(ctx context.Context, chainName string, port *int) (sdkutilities.StakingPool2, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.StakingPool2{}, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	sq := staking.NewQueryClient(grpcConn)
-	resp, err := sq.Pool(ctx, &staking.QueryPoolRequest{})
-	if err != nil {
-		return sdkutilities.StakingPool2{}, nil
-	}
-
-	respJSON, err := json.Marshal(resp)
-	if err != nil {
-		return sdkutilities.StakingPool2{}, fmt.Errorf("cannot json marshal response from staking pool, %w", err)
+// protoField returns the last occurrence of the length-delimited field num of
+// the protobuf message b.
+func protoField(b []byte, num protowire.Number) ([]byte, error) {
+	values, err := protoFields(b, num)
+	if err != nil || len(values) == 0 {
+		return nil, err
 	}
 
-	return sdkutilities.StakingPool2{
-		StakingPool: respJSON,
-	}, nil
+	return values[len(values)-1], nil
 }
 
-func emoneyInflation(ctx context.Context, chainName string, port *int) (sdkutilities.MintInflation2, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.MintInflation2{}, err
-	}
+// protoFields returns all occurrences of the length-delimited field num of
+// the protobuf message b.
+func protoFields(b []byte, num protowire.Number) ([][]byte, error) {
+	var ret [][]byte
 
-	defer func() {
-		_ = grpcConn.Close()
-	}()
+	for len(b) > 0 {
+		n, typ, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			return nil, protowire.ParseError(l)
+		}
+		b = b[l:]
 
-	emc := emoneyinflation.NewQueryClient(grpcConn)
-	resp, err := emc.Inflation(ctx, &emoneyinflation.QueryInflationRequest{})
-	if err != nil {
-		return sdkutilities.MintInflation2{}, nil
-	}
+		if n == num && typ == protowire.BytesType {
+			v, l := protowire.ConsumeBytes(b)
+			if l < 0 {
+				return nil, protowire.ParseError(l)
+			}
 
-	respJSON, err := json.Marshal(resp)
-	if err != nil {
-		return sdkutilities.MintInflation2{}, fmt.Errorf("cannot json marshal response from emoney inflation, %w", err)
-	}
+			ret = append(ret, v)
+			b = b[l:]
 
-	var ret sdkutilities.MintInflation2
-	var data sdkutilities.EmoneyInflation2
-	if err := json.Unmarshal(respJSON, &data); err != nil {
-		return sdkutilities.MintInflation2{}, fmt.Errorf("cannot json marshal response from mint inflation, %w", err)
-	}
+			continue
+		}
 
-	for _, v := range data.State.Assets {
-		if v.Denom == "ungm" {
-			ret.MintInflation = []byte(fmt.Sprintf("{\"inflation\":\"%s\"}", v.Inflation))
+		l = protowire.ConsumeFieldValue(n, typ, b)
+		if l < 0 {
+			return nil, protowire.ParseError(l)
 		}
+		b = b[l:]
 	}
 
 	return ret, nil
 }
-
-func DistributionParams(ctx context.Context, chainName string, port *int) (sdkutilities.DistributionParams2, error) {
-	if port == nil {
-		port = &grpcPort
-	}
-	grpcConn, err := grpc.Dial(fmt.Sprintf("%s:%d", chainName, *port), grpc.WithInsecure())
-	if err != nil {
-		return sdkutilities.DistributionParams2{}, err
-	}
-
-	defer func() {
-		_ = grpcConn.Close()
-	}()
-
-	dc := distribution.NewQueryClient(grpcConn)
-	resp, err := dc.Params(ctx, &distribution.QueryParamsRequest{})
-	if err != nil {
-		return sdkutilities.DistributionParams2{}, nil
-	}
-
-	respJSON, err := json.Marshal(resp)
-	if err != nil {
-		return sdkutilities.DistributionParams2{}, fmt.Errorf("cannot json marshal response from distribution params, %w", err)
-	}
-
-	return sdkutilities.DistributionParams2{
-		DistributionParams: respJSON,
-	}, nil
-}
-
-func BudgetParams(ctx context.Context, chainName string, port *int) (sdkutilities.BudgetParams2, error) {
-	return sdkutilities.BudgetParams2{}, fmt.Errorf("cannot get budget params from sdk")
-}
-
-func OsmoPools(ctx context.Context, chainName string, port *int) (sdkutilities.OsmoPools2, error) {
-	return sdkutilities.OsmoPools2{}, fmt.Errorf("cannot get osmosis pools - incorrect sdk version")
-}
-
-func CrescentPools(ctx context.Context, chainName string, port *int) (sdkutilities.CrescentPools2, error) {
-	return sdkutilities.CrescentPools2{}, fmt.Errorf("incorrect sdk version")
-}
@@ -0,0 +1,228 @@
+package sdkservice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+)
+
+// DefaultHealthCheckInterval is the default amount of time between two
+// health checks of the same node.
+const DefaultHealthCheckInterval = 15 * time.Second
+
+// DefaultMaxHeightLag is the default number of blocks a node can be behind
+// the most up-to-date node of its chain before being considered unhealthy.
+const DefaultMaxHeightLag = 10
+
+const healthCheckTimeout = 5 * time.Second
+
+// HealthConfig holds the thresholds used to decide whether a node is healthy.
+type HealthConfig struct {
+	// MaxHeightLag is the number of blocks a node can be behind the most
+	// up-to-date node of the chain.
+	MaxHeightLag int64 `yaml:"max_height_lag" json:"max_height_lag"`
+
+	// MaxBlockAge is the maximum age of the latest block of a node,
+	// disabled if zero.
+	MaxBlockAge Duration `yaml:"max_block_age" json:"max_block_age"`
+}
+
+func (h HealthConfig) maxHeightLag() int64 {
+	if h.MaxHeightLag == 0 {
+		return DefaultMaxHeightLag
+	}
+
+	return h.MaxHeightLag
+}
+
+// Health statuses of nodes.
+const (
+	// HealthUnknown is the status of nodes whose first health check hasn't
+	// completed yet.
+	HealthUnknown   = "unknown"
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// NodeHealth is the outcome of the last health check of a node.
+type NodeHealth struct {
+	Target string `json:"target"`
+	Status string `json:"status"`
+	// Healthy is false until the first health check of the node completes.
+	Healthy   bool      `json:"healthy"`
+	Syncing   bool      `json:"syncing"`
+	Height    int64     `json:"height"`
+	BlockTime time.Time `json:"block_time"`
+	CheckedAt time.Time `json:"checked_at"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// ChainHealth holds the health of all the nodes of a chain.
+type ChainHealth struct {
+	Chain string       `json:"chain"`
+	Nodes []NodeHealth `json:"nodes"`
+}
+
+// StartHealthChecks checks the health of the nodes of chains every interval
+// until the Upstream is closed. Nodes found unhealthy are only used when no
+// healthy node is left.
+func (u *Upstream) StartHealthChecks(chains []Chain, interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	u.stopHealth = cancel
+
+	u.healthWG.Add(1)
+	go func() {
+		defer u.healthWG.Done()
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			u.CheckHealth(ctx, chains)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+		}
+	}()
+}
+
+// CheckHealth checks the health of all the nodes of chains once.
+func (u *Upstream) CheckHealth(ctx context.Context, chains []Chain) {
+	var wg sync.WaitGroup
+	for _, c := range chains {
+		wg.Add(1)
+		go func(c Chain) {
+			defer wg.Done()
+			u.checkChain(ctx, c)
+		}(c)
+	}
+
+	wg.Wait()
+}
+
+// Health returns the health of the nodes of chain, as of their last check.
+// Nodes reached on ports other than the default one are listed once they
+// answered calls on them.
+func (u *Upstream) Health(chain Chain) ChainHealth {
+	ret := ChainHealth{
+		Chain: chain.Name,
+	}
+
+	for _, target := range u.targets(chain) {
+		h, checked := u.node(target).lastHealth()
+		if !checked {
+			h = NodeHealth{
+				Target: target,
+				Status: HealthUnknown,
+				Reason: "not checked yet",
+			}
+		}
+
+		ret.Nodes = append(ret.Nodes, h)
+	}
+
+	return ret
+}
+
+func (u *Upstream) checkChain(ctx context.Context, chain Chain) {
+	targets := u.targets(chain)
+	results := make([]NodeHealth, len(targets))
+
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = u.checkNode(ctx, chain, targets[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	var best int64
+	for _, r := range results {
+		if r.Reason == "" && r.Height > best {
+			best = r.Height
+		}
+	}
+
+	maxLag := chain.Health.maxHeightLag()
+	maxAge := time.Duration(chain.Health.MaxBlockAge)
+
+	for _, r := range results {
+		switch {
+		case r.Reason != "":
+		case r.Syncing:
+			r.Reason = "node is syncing"
+		case best-r.Height > maxLag:
+			r.Reason = fmt.Sprintf("node is %d blocks behind", best-r.Height)
+		case maxAge > 0 && r.CheckedAt.Sub(r.BlockTime) > maxAge:
+			r.Reason = fmt.Sprintf("latest block is older than %s", maxAge)
+		}
+
+		r.Healthy = r.Reason == ""
+		r.Status = HealthUnhealthy
+		if r.Healthy {
+			r.Status = HealthHealthy
+		}
+
+		u.node(r.Target).setHealth(r)
+	}
+}
+
+func (u *Upstream) checkNode(ctx context.Context, chain Chain, target string) NodeHealth {
+	ret := NodeHealth{
+		Target:    target,
+		CheckedAt: time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	conn, release, err := u.pool.Get(target, chain.TransportKey(), chain.DialOptions()...)
+	if err != nil {
+		ret.Reason = err.Error()
+		return ret
+	}
+	defer release()
+
+	if err := u.reverify(ctx, chain, target, conn); err != nil {
+		ret.Reason = err.Error()
+		return ret
+	}
+
+	sc := tmservice.NewServiceClient(conn)
+
+	syncing, err := sc.GetSyncing(ctx, &tmservice.GetSyncingRequest{})
+	if err != nil {
+		ret.Reason = fmt.Sprintf("cannot get syncing status, %s", err)
+		return ret
+	}
+
+	latest, err := sc.GetLatestBlock(ctx, &tmservice.GetLatestBlockRequest{})
+	if err != nil {
+		ret.Reason = fmt.Sprintf("cannot get latest block, %s", err)
+		return ret
+	}
+
+	if latest.Block == nil {
+		ret.Reason = "node returned no latest block"
+		return ret
+	}
+
+	ret.Syncing = syncing.Syncing
+	ret.Height = latest.Block.Header.Height
+	ret.BlockTime = latest.Block.Header.Time
+
+	return ret
+}
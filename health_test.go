@@ -0,0 +1,60 @@
+package sdkservice
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthUnknownUntilChecked(t *testing.T) {
+	node := newTestNode(t)
+
+	u := NewUpstream(NewConnPool(0))
+	defer u.Close()
+
+	chain := testChain(t, node.target, TLSConfig{}, AuthConfig{})
+
+	health := u.Health(chain)
+	require.Len(t, health.Nodes, 1)
+	require.Equal(t, HealthUnknown, health.Nodes[0].Status)
+	require.False(t, health.Nodes[0].Healthy)
+
+	u.CheckHealth(context.Background(), []Chain{chain})
+
+	health = u.Health(chain)
+	require.Equal(t, HealthHealthy, health.Nodes[0].Status)
+	require.True(t, health.Nodes[0].Healthy)
+	require.Equal(t, int64(42), health.Nodes[0].Height)
+}
+
+func TestHealthChecksQueriedPorts(t *testing.T) {
+	node := newTestNode(t)
+
+	host, portStr, err := net.SplitHostPort(node.target)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	u := NewUpstream(NewConnPool(0))
+	defer u.Close()
+
+	// The endpoint has no port, queries give the one of the node.
+	chain := testChain(t, host, TLSConfig{}, AuthConfig{})
+	require.Equal(t, []string{chain.GRPCTarget(0, nil)}, u.targets(chain))
+
+	ctx := context.Background()
+	_, err = tmservice.NewServiceClient(u.Conn(chain, &port)).GetNodeInfo(ctx, &tmservice.GetNodeInfoRequest{})
+	require.NoError(t, err)
+
+	u.CheckHealth(ctx, []Chain{chain})
+
+	health := u.Health(chain)
+	require.Len(t, health.Nodes, 2)
+	require.Equal(t, chain.GRPCTarget(0, nil), health.Nodes[0].Target)
+	require.Equal(t, node.target, health.Nodes[1].Target)
+	require.Equal(t, HealthHealthy, health.Nodes[1].Status)
+}
@@ -0,0 +1,222 @@
+package sdkservice
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// DefaultImmutableCacheSize is the default amount of memory, in bytes, used
+// to cache finalized blocks and transactions.
+const DefaultImmutableCacheSize = 64 << 20
+
+// DefaultImmutableCacheDiskSize is the default size, in bytes, of the
+// on-disk store of finalized blocks and transactions.
+const DefaultImmutableCacheDiskSize = 1 << 30
+
+// ImmutableCacheOptions holds the settings of an ImmutableCache.
+type ImmutableCacheOptions struct {
+	// MaxMemoryBytes is the amount of memory used by the in-memory LRU,
+	// disabled if zero.
+	MaxMemoryBytes int64
+
+	// Path is the path of the on-disk store, disabled if empty.
+	Path string
+
+	// MaxDiskBytes is the amount of data kept in the on-disk store, once
+	// reached the oldest entries are evicted first.
+	MaxDiskBytes int64
+}
+
+// ImmutableCacheStats holds the counters of an ImmutableCache.
+type ImmutableCacheStats struct {
+	MemoryHits      uint64 `json:"memory_hits"`
+	DiskHits        uint64 `json:"disk_hits"`
+	Misses          uint64 `json:"misses"`
+	MemoryEvictions uint64 `json:"memory_evictions"`
+	DiskEvictions   uint64 `json:"disk_evictions"`
+	DiskErrors      uint64 `json:"disk_errors"`
+	MemoryEntries   int    `json:"memory_entries"`
+	MemoryBytes     int64  `json:"memory_bytes"`
+	DiskEntries     int    `json:"disk_entries"`
+	DiskBytes       int64  `json:"disk_bytes"`
+}
+
+// ImmutableCache keeps data which never changes once finalized, like blocks
+// and transactions, in a bounded in-memory LRU backed by an optional
+// on-disk store.
+type ImmutableCache struct {
+	maxBytes int64
+	disk     *diskStore
+
+	mu    sync.Mutex
+	size  int64
+	ll    *list.List
+	items map[string]*list.Element
+	stats ImmutableCacheStats
+}
+
+type immutableEntry struct {
+	key   string
+	value []byte
+}
+
+func (e *immutableEntry) size() int64 {
+	return int64(len(e.key) + len(e.value))
+}
+
+// NewImmutableCache returns an ImmutableCache configured with opts, opening
+// its on-disk store if any.
+func NewImmutableCache(opts ImmutableCacheOptions) (*ImmutableCache, error) {
+	c := &ImmutableCache{
+		maxBytes: opts.MaxMemoryBytes,
+		ll:       list.New(),
+		items:    map[string]*list.Element{},
+	}
+
+	if opts.Path != "" {
+		d, err := openDiskStore(opts.Path, opts.MaxDiskBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		c.disk = d
+	}
+
+	return c, nil
+}
+
+// Do returns the value held for key, calling fetch and storing its result
+// on misses. Errors are never cached.
+// Bypassing the cache skips the lookup, but still stores the fetched value.
+func (c *ImmutableCache) Do(ctx context.Context, key string, fetch func() ([]byte, error)) ([]byte, error) {
+	if !cacheBypassed(ctx) {
+		if v, ok := c.Get(key); ok {
+			return v, nil
+		}
+	}
+
+	v, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.Put(key, v)
+
+	return v, nil
+}
+
+// Get returns the value held for key, looking in memory first and then on
+// disk.
+func (c *ImmutableCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	el, ok := c.items[key]
+	if ok {
+		c.ll.MoveToFront(el)
+		c.stats.MemoryHits++
+	}
+	c.mu.Unlock()
+
+	if ok {
+		return el.Value.(*immutableEntry).value, true
+	}
+
+	var v []byte
+	var err error
+	if c.disk != nil {
+		v, err = c.disk.get(key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case err != nil:
+		c.stats.DiskErrors++
+	case v != nil:
+		c.stats.DiskHits++
+		c.addLocked(key, v)
+		return v, true
+	}
+
+	c.stats.Misses++
+	return nil, false
+}
+
+// Put stores value for key in memory and on disk.
+func (c *ImmutableCache) Put(key string, value []byte) {
+	c.mu.Lock()
+	c.addLocked(key, value)
+	c.mu.Unlock()
+
+	if c.disk == nil {
+		return
+	}
+
+	evicted, err := c.disk.put(key, value)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.DiskEvictions += uint64(evicted)
+	if err != nil {
+		c.stats.DiskErrors++
+	}
+}
+
+// Stats returns the counters of the cache.
+func (c *ImmutableCache) Stats() ImmutableCacheStats {
+	c.mu.Lock()
+	ret := c.stats
+	ret.MemoryEntries = c.ll.Len()
+	ret.MemoryBytes = c.size
+	c.mu.Unlock()
+
+	if c.disk != nil {
+		entries, size, err := c.disk.stats()
+		if err == nil {
+			ret.DiskEntries = entries
+			ret.DiskBytes = size
+		}
+	}
+
+	return ret
+}
+
+// Close closes the on-disk store.
+func (c *ImmutableCache) Close() error {
+	if c.disk == nil {
+		return nil
+	}
+
+	return c.disk.close()
+}
+
+func (c *ImmutableCache) addLocked(key string, value []byte) {
+	e := &immutableEntry{
+		key:   key,
+		value: value,
+	}
+
+	if e.size() > c.maxBytes {
+		return
+	}
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(e)
+	c.size += e.size()
+
+	for c.size > c.maxBytes {
+		el := c.ll.Back()
+		old := el.Value.(*immutableEntry)
+
+		c.ll.Remove(el)
+		delete(c.items, old.key)
+		c.size -= old.size()
+		c.stats.MemoryEvictions++
+	}
+}
@@ -0,0 +1,133 @@
+package sdkservice
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// value9 returns a 9 bytes value, making entries of one byte keys 10 bytes.
+func value9(b byte) []byte {
+	return []byte{b, b, b, b, b, b, b, b, b}
+}
+
+func TestImmutableCacheMemoryEviction(t *testing.T) {
+	c, err := NewImmutableCache(ImmutableCacheOptions{MaxMemoryBytes: 30})
+	require.NoError(t, err)
+
+	for _, k := range []string{"a", "b", "c"} {
+		c.Put(k, value9(k[0]))
+	}
+
+	// a becomes the most recently used, evicting b instead.
+	_, ok := c.Get("a")
+	require.True(t, ok)
+
+	c.Put("d", value9('d'))
+
+	_, ok = c.Get("b")
+	require.False(t, ok)
+
+	for _, k := range []string{"a", "c", "d"} {
+		v, ok := c.Get(k)
+		require.True(t, ok, k)
+		require.Equal(t, value9(k[0]), v, k)
+	}
+
+	// Entries larger than the cache aren't kept.
+	c.Put("e", make([]byte, 30))
+	_, ok = c.Get("e")
+	require.False(t, ok)
+
+	stats := c.Stats()
+	require.Equal(t, uint64(1), stats.MemoryEvictions)
+	require.Equal(t, 3, stats.MemoryEntries)
+	require.Equal(t, int64(30), stats.MemoryBytes)
+	require.Equal(t, uint64(2), stats.Misses)
+}
+
+func TestImmutableCacheDiskEviction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	c, err := NewImmutableCache(ImmutableCacheOptions{Path: path, MaxDiskBytes: 30})
+	require.NoError(t, err)
+
+	for _, k := range []string{"a", "b", "c"} {
+		c.Put(k, value9(k[0]))
+	}
+
+	// Reads don't change the order of eviction on disk, the oldest entry
+	// goes first.
+	_, ok := c.Get("a")
+	require.True(t, ok)
+
+	c.Put("d", value9('d'))
+
+	stats := c.Stats()
+	require.Equal(t, uint64(1), stats.DiskEvictions)
+	require.Equal(t, 3, stats.DiskEntries)
+	require.Equal(t, int64(30), stats.DiskBytes)
+	require.Zero(t, stats.DiskErrors)
+
+	require.NoError(t, c.Close())
+
+	// Entries survive restarts.
+	c, err = NewImmutableCache(ImmutableCacheOptions{MaxMemoryBytes: 100, Path: path, MaxDiskBytes: 30})
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, ok = c.Get("a")
+	require.False(t, ok)
+
+	for _, k := range []string{"b", "c", "d"} {
+		v, ok := c.Get(k)
+		require.True(t, ok, k)
+		require.Equal(t, value9(k[0]), v, k)
+	}
+
+	// Disk hits are then served from memory.
+	_, ok = c.Get("b")
+	require.True(t, ok)
+
+	stats = c.Stats()
+	require.Equal(t, uint64(3), stats.DiskHits)
+	require.Equal(t, uint64(1), stats.MemoryHits)
+	require.Equal(t, 3, stats.MemoryEntries)
+}
+
+func TestImmutableCacheErrors(t *testing.T) {
+	c, err := NewImmutableCache(ImmutableCacheOptions{MaxMemoryBytes: 100})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	errFetch := errors.New("node unavailable")
+
+	var calls int
+	fetch := func() ([]byte, error) {
+		calls++
+		if calls == 1 {
+			return nil, errFetch
+		}
+
+		return []byte("block"), nil
+	}
+
+	_, err = c.Do(ctx, "block/42", fetch)
+	require.Equal(t, errFetch, err)
+
+	// The error wasn't cached, the value is fetched again then kept.
+	for i := 0; i < 2; i++ {
+		v, err := c.Do(ctx, "block/42", fetch)
+		require.NoError(t, err)
+		require.Equal(t, []byte("block"), v)
+	}
+	require.Equal(t, 2, calls)
+
+	// Bypassing the cache fetches the value anyway.
+	_, err = c.Do(WithoutCache(ctx), "block/42", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+}
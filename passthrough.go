@@ -0,0 +1,109 @@
+package sdkservice
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DefaultPassthroughServices are the gRPC services which can be queried
+// through Passthrough unless configured otherwise. Only query services should
+// be allowed, Passthrough doesn't tell reads and writes apart.
+var DefaultPassthroughServices = []string{
+	"cosmos.auth.v1beta1.Query",
+	"cosmos.authz.v1beta1.Query",
+	"cosmos.bank.v1beta1.Query",
+	"cosmos.distribution.v1beta1.Query",
+	"cosmos.evidence.v1beta1.Query",
+	"cosmos.feegrant.v1beta1.Query",
+	"cosmos.gov.v1beta1.Query",
+	"cosmos.mint.v1beta1.Query",
+	"cosmos.params.v1beta1.Query",
+	"cosmos.slashing.v1beta1.Query",
+	"cosmos.staking.v1beta1.Query",
+	"cosmos.upgrade.v1beta1.Query",
+	"ibc.applications.transfer.v1.Query",
+	"ibc.core.channel.v1.Query",
+	"ibc.core.client.v1.Query",
+	"ibc.core.connection.v1.Query",
+}
+
+// passthroughMethod holds the request and response types of a gRPC method.
+type passthroughMethod struct {
+	request  reflect.Type
+	response reflect.Type
+}
+
+// Passthrough invokes method, in the /package.Service/Method form, with the
+// proto-JSON encoded request. The response is returned as proto-JSON.
+func (q *chainQuerier) Passthrough(ctx context.Context, method string, request []byte) ([]byte, error) {
+	m, err := q.resolveMethod(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+
+	cdc := q.cdc
+
+	req := reflect.New(m.request).Interface().(gogoproto.Message)
+	if len(bytes.TrimSpace(request)) > 0 {
+		if err := cdc.UnmarshalJSON(request, req); err != nil {
+			return nil, newError(KindInvalidArgument, fmt.Errorf("cannot unmarshal request of %s, %w", method, err))
+		}
+	}
+
+	resp := reflect.New(m.response).Interface().(gogoproto.Message)
+	if err := q.conn.Invoke(ctx, method, req, resp); err != nil {
+		return nil, queryError(method, err)
+	}
+
+	return q.marshalJSON(ctx, resp)
+}
+
+// serviceName returns the service of method, in the package.Service form.
+func serviceName(method string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(method, "/"), "/")
+	if !strings.HasPrefix(method, "/") || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", newError(KindInvalidArgument, fmt.Errorf("malformed method %q, expected /package.Service/Method", method))
+	}
+
+	return parts[0], nil
+}
+
+// resolveMethod returns the request and response types of method, as
+// declared by the descriptor of its service.
+func (q *chainQuerier) resolveMethod(ctx context.Context, method string) (passthroughMethod, error) {
+	service, err := serviceName(method)
+	if err != nil {
+		return passthroughMethod{}, err
+	}
+
+	sd, err := q.types.findService(ctx, q.conn, protoreflect.FullName(service))
+	if err != nil {
+		return passthroughMethod{}, err
+	}
+
+	md := sd.Methods().ByName(protoreflect.Name(method[len(service)+2:]))
+	if md == nil {
+		return passthroughMethod{}, newError(KindNotFound, fmt.Errorf("unknown method %s", method))
+	}
+
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return passthroughMethod{}, newError(KindInvalidArgument, fmt.Errorf("streaming method %s not supported", method))
+	}
+
+	req := gogoproto.MessageType(string(md.Input().FullName()))
+	resp := gogoproto.MessageType(string(md.Output().FullName()))
+	if req == nil || resp == nil {
+		return passthroughMethod{}, newError(KindNotFound, fmt.Errorf("types of %s not registered", method))
+	}
+
+	return passthroughMethod{
+		request:  req.Elem(),
+		response: resp.Elem(),
+	}, nil
+}
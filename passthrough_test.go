@@ -0,0 +1,84 @@
+package sdkservice
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const balanceMethod = "/cosmos.bank.v1beta1.Query/Balance"
+
+func TestPassthrough(t *testing.T) {
+	// Compiled in services are resolved without the nodes, fakeConn doesn't
+	// serve reflection.
+	conn := newFakeConn(map[string]interface{}{
+		balanceMethod: fakeReply(func(args interface{}) (interface{}, error) {
+			req := args.(*bank.QueryBalanceRequest)
+			coin := sdktypes.NewInt64Coin(req.Denom, 42)
+
+			return &bank.QueryBalanceResponse{Balance: &coin}, nil
+		}),
+	})
+	q := testQuerier("cosmos-hub", SDKv44, conn)
+
+	ret, err := q.Passthrough(context.Background(), balanceMethod, []byte(`{"address":"cosmos1abcd","denom":"uatom"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"balance":{"denom":"uatom","amount":"42"}}`, string(ret))
+
+	_, err = q.Passthrough(context.Background(), "/cosmos.bank.v1beta1.Query/Unknown", nil)
+	require.Equal(t, KindNotFound, ClassifyError(err).Kind)
+
+	_, err = q.Passthrough(context.Background(), "/cosmos.base.tendermint.v1beta1.Service/GetNodeInfo", nil)
+	require.NotEqual(t, KindNotFound, ClassifyError(err).Kind)
+	require.Equal(t, 1, conn.called("/cosmos.base.tendermint.v1beta1.Service/GetNodeInfo"))
+
+	_, err = q.Passthrough(context.Background(), balanceMethod, []byte(`{"address":`))
+	require.Equal(t, KindInvalidArgument, ClassifyError(err).Kind)
+
+	_, err = q.Passthrough(context.Background(), "cosmos.bank.v1beta1.Query/Balance", nil)
+	require.Equal(t, KindInvalidArgument, ClassifyError(err).Kind)
+}
+
+func TestResolveMethodFromReflection(t *testing.T) {
+	// Methods are resolved from the service the nodes declare, whatever the
+	// names of their types.
+	f := &fakeReflection{}
+	q := &chainQuerier{
+		conn:  newReflectionConn(t, f),
+		types: newDescriptorResolver(),
+	}
+
+	m, err := q.resolveMethod(context.Background(), "/test.S/Get")
+	require.NoError(t, err)
+	require.Equal(t, reflect.TypeOf(bank.QueryBalanceRequest{}), m.request)
+	require.Equal(t, reflect.TypeOf(bank.QueryBalanceResponse{}), m.response)
+
+	_, err = q.resolveMethod(context.Background(), "/test.S/Watch")
+	require.Equal(t, KindInvalidArgument, ClassifyError(err).Kind)
+
+	_, err = q.resolveMethod(context.Background(), "/test.S/Unknown")
+	require.Equal(t, KindNotFound, ClassifyError(err).Kind)
+	require.Equal(t, 1, f.called("test/s.proto"))
+}
+
+func TestStaticServiceFile(t *testing.T) {
+	for name, file := range map[protoreflect.FullName]string{
+		"cosmos.bank.v1beta1.Query":              "cosmos/bank/v1beta1/query.proto",
+		"cosmos.bank.v1beta1.Msg":                "cosmos/bank/v1beta1/tx.proto",
+		"cosmos.tx.v1beta1.Service":              "cosmos/tx/v1beta1/service.proto",
+		"cosmos.base.tendermint.v1beta1.Service": "cosmos/base/tendermint/v1beta1/query.proto",
+		"ethermint.evm.v1.Msg":                   ethermintTxFile,
+	} {
+		fd, ok := staticServiceFile(name)
+		require.True(t, ok, name)
+		require.Equal(t, file, fd.GetName(), name)
+	}
+
+	_, ok := staticServiceFile("cosmos.bank.v1beta1.Unknown")
+	require.False(t, ok)
+}
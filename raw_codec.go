@@ -0,0 +1,36 @@
+package sdkservice
+
+import (
+	"fmt"
+
+	encodingproto "google.golang.org/grpc/encoding/proto"
+)
+
+// rawCodec passes messages to and from the upstream as bytes, for queries to
+// modules whose types aren't compiled in the service.
+// Messages must be of type *[]byte.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("raw codec: expected *[]byte, got %T", v)
+	}
+
+	return *b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("raw codec: expected *[]byte, got %T", v)
+	}
+
+	*b = append((*b)[:0], data...)
+
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return encodingproto.Name
+}
@@ -2,29 +2,285 @@ package sdkservice
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/emerishq/sdk-service-meta/gen/log"
 	sdkutilities "github.com/emerishq/sdk-service-meta/gen/sdk_utilities"
 )
 
+var grpcPort = 9090
+
+// Service is the sdk-utilities service, which must be closed once the
+// server exits to release upstream connections.
+type Service interface {
+	sdkutilities.Service
+	io.Closer
+
+	// NodesHealth returns the health of the nodes of all the configured chains.
+	NodesHealth() []ChainHealth
+
+	// CacheStats returns the counters of the service caches.
+	CacheStats() CacheReport
+
+	// Passthrough invokes method on chainName with the proto-JSON encoded
+	// request, returning the proto-JSON encoded response. Only the methods
+	// of the allowed services can be invoked.
+	Passthrough(ctx context.Context, chainName string, method string, request []byte) ([]byte, error)
+
+	// TxSummary decodes the messages, fee and signers of txBytes with the
+	// codec of chainName, including the types of its codec extensions.
+	TxSummary(ctx context.Context, chainName string, txBytes []byte) (TxSummary, error)
+
+	// Broadcast sends txBytes to chainName with the given options.
+	Broadcast(ctx context.Context, chainName string, txBytes []byte, opts BroadcastOptions) (BroadcastResult, error)
+
+	// TxStatus returns the status of the transaction hash broadcast to
+	// chainName, when transaction tracking is enabled.
+	TxStatus(ctx context.Context, chainName string, hash string) (TrackedTx, error)
+
+	// SearchTxs returns the page of transactions of chainName matching
+	// search.
+	SearchTxs(ctx context.Context, chainName string, search TxSearch) (TxSearchResult, error)
+
+	// Balances returns the total, spendable and locked balances of address
+	// on chainName.
+	Balances(ctx context.Context, chainName string, address AccountAddress) (Balances, error)
+
+	// AccountDetails returns the type, numbers, public key and vesting
+	// schedule of address on chainName.
+	AccountDetails(ctx context.Context, chainName string, address AccountAddress) (AccountDetails, error)
+
+	// ConvertAddress converts address to bech32 with prefix and to the
+	// addresses of the configured chains, without reaching any node.
+	ConvertAddress(address string, prefix string) (AddressInfo, error)
+}
+
+// CacheReport holds the counters of the service caches.
+type CacheReport struct {
+	// Endpoints holds the hit and miss counters of each endpoint cached for
+	// a limited amount of time.
+	Endpoints map[string]CacheStats `json:"endpoints"`
+
+	// Immutable holds the counters of the finalized blocks and transactions
+	// cache.
+	Immutable ImmutableCacheStats `json:"immutable"`
+}
+
+// Options holds the settings of the sdk-utilities service.
+type Options struct {
+	// IdleTimeout is the amount of time after which unused upstream
+	// connections are closed, disabled if zero.
+	IdleTimeout time.Duration
+
+	// HealthCheckInterval is the amount of time between two health checks
+	// of the upstream nodes, disabled if zero.
+	HealthCheckInterval time.Duration
+
+	// CacheTTLs holds how long the results of each endpoint are cached,
+	// endpoints without a TTL are not cached.
+	CacheTTLs map[string]time.Duration
+
+	// ImmutableCache holds the settings of the finalized blocks and
+	// transactions cache.
+	ImmutableCache ImmutableCacheOptions
+
+	// PassthroughServices are the gRPC services, like
+	// cosmos.gov.v1beta1.Query, which can be queried through Passthrough.
+	PassthroughServices []string
+
+	// Tracker holds the settings of the broadcast transactions tracker.
+	Tracker TrackerOptions
+}
+
 // sdk-utilities service example implementation.
 // The example methods log the requests and return zero values.
 type sdkUtilitiessrvc struct {
-	logger *log.Logger
-	debug  bool
+	logger   *log.Logger
+	debug    bool
+	chains   *ChainRegistry
+	upstream *Upstream
+	cache    *TTLCache
+	blobs    *ImmutableCache
+	versions *sdkVersions
+	types    *descriptorResolvers
+	codecs   *chainCodecs
+	tracker  *TxTracker
+
+	passthroughServices map[string]bool
+}
+
+// NewSdkUtilities returns the sdk-utilities service implementation, serving
+// the chains held in the chains registry.
+func NewSdkUtilities(logger *log.Logger, debug bool, chains *ChainRegistry, opts Options) (Service, error) {
+	blobs, err := NewImmutableCache(opts.ImmutableCache)
+	if err != nil {
+		return nil, err
+	}
+
+	upstream := NewUpstream(NewConnPool(opts.IdleTimeout))
+	if opts.HealthCheckInterval > 0 {
+		upstream.StartHealthChecks(chains.Chains(), opts.HealthCheckInterval)
+	}
+
+	passthroughServices := map[string]bool{}
+	for _, svc := range opts.PassthroughServices {
+		passthroughServices[svc] = true
+	}
+
+	s := &sdkUtilitiessrvc{
+		logger:   logger,
+		debug:    debug,
+		chains:   chains,
+		upstream: upstream,
+		cache:    NewTTLCache(opts.CacheTTLs),
+		blobs:    blobs,
+		versions: newSDKVersions(),
+		types:    newDescriptorResolvers(),
+		codecs:   newChainCodecs(),
+
+		passthroughServices: passthroughServices,
+	}
+
+	if opts.Tracker.Enabled {
+		s.tracker, err = newTxTracker(opts.Tracker, s.lookupTx, logger)
+		if err != nil {
+			_ = s.Close()
+			return nil, fmt.Errorf("cannot start transaction tracker, %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+func (s *sdkUtilitiessrvc) NodesHealth() []ChainHealth {
+	chains := s.chains.Chains()
+
+	ret := make([]ChainHealth, 0, len(chains))
+	for _, c := range chains {
+		ret = append(ret, s.upstream.Health(c))
+	}
+
+	return ret
+}
+
+func (s *sdkUtilitiessrvc) CacheStats() CacheReport {
+	return CacheReport{
+		Endpoints: s.cache.Stats(),
+		Immutable: s.blobs.Stats(),
+	}
+}
+
+func (s *sdkUtilitiessrvc) Passthrough(ctx context.Context, chainName string, method string, request []byte) ([]byte, error) {
+	service, err := serviceName(method)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.passthroughServices[service] {
+		return nil, newError(KindForbidden, fmt.Errorf("service %s not allowed", service))
+	}
+
+	q, err := s.querier(ctx, chainName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.Passthrough(ctx, method, request)
+}
+
+func (s *sdkUtilitiessrvc) TxStatus(ctx context.Context, chainName string, hash string) (TrackedTx, error) {
+	if s.tracker == nil {
+		return TrackedTx{}, newError(KindNotFound, fmt.Errorf("transaction tracking disabled"))
+	}
+
+	return s.tracker.Status(chainName, hash)
 }
 
-// NewSdkUtilities returns the sdk-utilities service implementation.
-func NewSdkUtilities(logger *log.Logger, debug bool) sdkutilities.Service {
-	return &sdkUtilitiessrvc{
-		logger: logger,
-		debug:  debug,
+func (s *sdkUtilitiessrvc) SearchTxs(ctx context.Context, chainName string, search TxSearch) (TxSearchResult, error) {
+	q, err := s.querier(ctx, chainName, nil)
+	if err != nil {
+		return TxSearchResult{}, err
+	}
+
+	return q.SearchTxs(ctx, search)
+}
+
+// lookupTx returns the response of the transaction hash on chainName, or nil
+// if it isn't in a block yet.
+func (s *sdkUtilitiessrvc) lookupTx(ctx context.Context, chainName string, hash string) (*sdktypes.TxResponse, error) {
+	q, err := s.querier(ctx, chainName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.lookupTx(ctx, hash)
+}
+
+// broadcast broadcasts txBytes through q, registering it in the tracker.
+func (s *sdkUtilitiessrvc) broadcast(ctx context.Context, q *chainQuerier, txBytes []byte, opts BroadcastOptions) (BroadcastResult, error) {
+	res, err := q.Broadcast(ctx, txBytes, opts)
+	if err != nil {
+		return BroadcastResult{}, err
+	}
+
+	if s.tracker != nil && res.Hash != "" {
+		s.tracker.Track(q.chain.Name, res)
+	}
+
+	return res, nil
+}
+
+// Close stops the transactions tracker, and closes all the upstream
+// connections and the on-disk cache.
+func (s *sdkUtilitiessrvc) Close() error {
+	if s.tracker != nil {
+		s.tracker.Close()
+	}
+
+	if err := s.upstream.Close(); err != nil {
+		_ = s.blobs.Close()
+		return err
+	}
+
+	return s.blobs.Close()
+}
+
+// querier returns the querier of chainName, through the adapter matching the
+// cosmos-sdk version it runs.
+func (s *sdkUtilitiessrvc) querier(ctx context.Context, chainName string, port *int) (*chainQuerier, error) {
+	chain, err := s.chains.Chain(chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcConn := s.upstream.Conn(chain, port)
+
+	adapter, err := s.versions.adapter(ctx, chain, grpcConn)
+	if err != nil {
+		return nil, err
 	}
+
+	return &chainQuerier{
+		chain: chain,
+		conn:  grpcConn,
+		sdk:   adapter,
+		cdc:   s.codecs.get(adapter, chain),
+		types: s.types.get(chain.Name),
+	}, nil
 }
 
 // Supply implements supply.
 func (s *sdkUtilitiessrvc) Supply(ctx context.Context, payload *sdkutilities.SupplyPayload) (res *sdkutilities.Supply2, err error) {
-	ret, err := QuerySupply(ctx, payload.ChainName, payload.Port, payload.PaginationKey)
+	q, err := s.querier(ctx, payload.ChainName, payload.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := q.QuerySupply(ctx, payload.PaginationKey)
 	if err != nil {
 		return nil, err
 	}
@@ -35,127 +291,447 @@ func (s *sdkUtilitiessrvc) Supply(ctx context.Context, payload *sdkutilities.Sup
 }
 
 func (s *sdkUtilitiessrvc) SupplyDenom(ctx context.Context, payload *sdkutilities.SupplyDenomPayload) (res *sdkutilities.Supply2, err error) {
-	return SupplyDenom(ctx, payload.ChainName, payload.Port, payload.Denom)
+	q, err := s.querier(ctx, payload.ChainName, payload.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.SupplyDenom(ctx, payload.Denom)
 }
 
 func (s *sdkUtilitiessrvc) QueryTx(ctx context.Context, payload *sdkutilities.QueryTxPayload) (res []byte, err error) {
-	return GetTxFromHash(ctx, payload.ChainName, payload.Port, payload.Hash)
+	key := cacheKey(payload.ChainName, "tx", strings.ToUpper(payload.Hash))
+
+	return s.blobs.Do(ctx, key, func() ([]byte, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		return q.GetTxFromHash(ctx, payload.Hash)
+	})
 }
 
 func (s *sdkUtilitiessrvc) BroadcastTx(ctx context.Context, payload *sdkutilities.BroadcastTxPayload) (res *sdkutilities.TransactionResult, err error) {
-	txHash, txErr := BroadcastTx(
-		ctx,
-		payload.ChainName,
-		payload.Port,
-		payload.TxBytes,
-	)
+	q, err := s.querier(ctx, payload.ChainName, payload.Port)
+	if err != nil {
+		return nil, err
+	}
 
-	if txErr != nil {
-		err = txErr
-		return
+	txRes, err := s.broadcast(ctx, q, payload.TxBytes, BroadcastOptions{Mode: BroadcastSync})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := txRes.Err(); err != nil {
+		return nil, err
 	}
 
 	res = &sdkutilities.TransactionResult{
-		Hash: txHash,
+		Hash: txRes.Hash,
 	}
 
 	return
 }
 
+func (s *sdkUtilitiessrvc) Broadcast(ctx context.Context, chainName string, txBytes []byte, opts BroadcastOptions) (BroadcastResult, error) {
+	q, err := s.querier(ctx, chainName, nil)
+	if err != nil {
+		return BroadcastResult{}, err
+	}
+
+	return s.broadcast(ctx, q, txBytes, opts)
+}
+
+// TxMetadata decodes payload with the codec of the latest SDK generation.
+// Its payload doesn't name a chain, so the types of codec extensions, like
+// Ethermint's MsgEthereumTx and eth_secp256k1 keys, aren't decoded: they
+// are through TxSummary, which uses the codec of the chain.
 func (s *sdkUtilitiessrvc) TxMetadata(ctx context.Context, payload *sdkutilities.TxMetadataPayload) (res *sdkutilities.TxMessagesMetadata, err error) {
 	var ret sdkutilities.TxMessagesMetadata
-	ret, err = TxMetadata(ctx, payload.TxBytes)
+	ret, err = txMetadata(sdkAdapters[defaultSDKVersion].Codec(), payload.TxBytes)
 	res = &ret
 	return
 }
 
+func (s *sdkUtilitiessrvc) TxSummary(ctx context.Context, chainName string, txBytes []byte) (TxSummary, error) {
+	q, err := s.querier(ctx, chainName, nil)
+	if err != nil {
+		return TxSummary{}, err
+	}
+
+	return summarizeTx(q.cdc, txBytes)
+}
+
 func (s *sdkUtilitiessrvc) Block(ctx context.Context, payload *sdkutilities.BlockPayload) (res *sdkutilities.BlockData, err error) {
-	ret, err := Block(ctx, payload.ChainName, payload.Port, payload.Height)
-	return &ret, err
+	key := cacheKey(payload.ChainName, "block", payload.Height)
+
+	block, err := s.blobs.Do(ctx, key, func() ([]byte, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.Block(ctx, payload.Height)
+		if err != nil {
+			return nil, err
+		}
+
+		return ret.Block, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdkutilities.BlockData{
+		Height: payload.Height,
+		Block:  block,
+	}, nil
 }
 
 // LiquidityParams implements liquidityParams.
-func (s *sdkUtilitiessrvc) LiquidityParams(ctx context.Context, payload *sdkutilities.LiquidityParamsPayload) (res *sdkutilities.LiquidityParams2, err error) {
-	ret, err := LiquidityParams(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+func (s *sdkUtilitiessrvc) LiquidityParams(ctx context.Context, payload *sdkutilities.LiquidityParamsPayload) (*sdkutilities.LiquidityParams2, error) {
+	ret, err := s.cache.Do(ctx, "liquidityParams", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.LiquidityParams(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.LiquidityParams2), nil
 }
 
 // LiquidityPools implements liquidityPools.
-func (s *sdkUtilitiessrvc) LiquidityPools(ctx context.Context, payload *sdkutilities.LiquidityPoolsPayload) (res *sdkutilities.LiquidityPools2, err error) {
-	ret, err := LiquidityPools(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+func (s *sdkUtilitiessrvc) LiquidityPools(ctx context.Context, payload *sdkutilities.LiquidityPoolsPayload) (*sdkutilities.LiquidityPools2, error) {
+	ret, err := s.cache.Do(ctx, "liquidityPools", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.LiquidityPools(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.LiquidityPools2), nil
 }
 
 // MintInflation implements mintInflation.
-func (s *sdkUtilitiessrvc) MintInflation(ctx context.Context, payload *sdkutilities.MintInflationPayload) (res *sdkutilities.MintInflation2, err error) {
-	ret, err := MintInflation(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+func (s *sdkUtilitiessrvc) MintInflation(ctx context.Context, payload *sdkutilities.MintInflationPayload) (*sdkutilities.MintInflation2, error) {
+	ret, err := s.cache.Do(ctx, "mintInflation", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.MintInflation(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.MintInflation2), nil
 }
 
 // MintParams implements mintParams.
-func (s *sdkUtilitiessrvc) MintParams(ctx context.Context, payload *sdkutilities.MintParamsPayload) (res *sdkutilities.MintParams2, err error) {
-	ret, err := MintParams(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+func (s *sdkUtilitiessrvc) MintParams(ctx context.Context, payload *sdkutilities.MintParamsPayload) (*sdkutilities.MintParams2, error) {
+	ret, err := s.cache.Do(ctx, "mintParams", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.MintParams(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.MintParams2), nil
 }
 
 // MintAnnualProvision implements mintAnnualProvision.
-func (s *sdkUtilitiessrvc) MintAnnualProvision(ctx context.Context, payload *sdkutilities.MintAnnualProvisionPayload) (res *sdkutilities.MintAnnualProvision2, err error) {
-	ret, err := MintAnnualProvision(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+func (s *sdkUtilitiessrvc) MintAnnualProvision(ctx context.Context, payload *sdkutilities.MintAnnualProvisionPayload) (*sdkutilities.MintAnnualProvision2, error) {
+	ret, err := s.cache.Do(ctx, "mintAnnualProvision", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.MintAnnualProvision(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.MintAnnualProvision2), nil
 }
 
 // MintEpochProvisions implements mintEpochProvisions.
-func (s *sdkUtilitiessrvc) MintEpochProvisions(ctx context.Context, payload *sdkutilities.MintEpochProvisionsPayload) (res *sdkutilities.MintEpochProvisions2, err error) {
-	ret, err := MintEpochProvisions(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+func (s *sdkUtilitiessrvc) MintEpochProvisions(ctx context.Context, payload *sdkutilities.MintEpochProvisionsPayload) (*sdkutilities.MintEpochProvisions2, error) {
+	ret, err := s.cache.Do(ctx, "mintEpochProvisions", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.MintEpochProvisions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.MintEpochProvisions2), nil
 }
 
 func (s *sdkUtilitiessrvc) AccountNumbers(ctx context.Context, payload *sdkutilities.AccountNumbersPayload) (res *sdkutilities.AccountNumbers2, err error) {
-	ret, err := AccountNumbers(ctx, payload.ChainName, payload.Port, *payload.AddresHex, *payload.Bech32Prefix)
+	q, err := s.querier(ctx, payload.ChainName, payload.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := q.AccountNumbers(ctx, payloadAddress(payload.AddresHex, payload.Bech32Prefix))
 	return &ret, err
 }
 
+// payloadAddress returns the account given by the optional hex address and
+// bech32 prefix of a payload.
+func payloadAddress(addressHex *string, bech32Prefix *string) AccountAddress {
+	ret := AccountAddress{}
+
+	if addressHex != nil {
+		ret.Hex = *addressHex
+	}
+
+	if bech32Prefix != nil {
+		ret.Bech32Prefix = *bech32Prefix
+	}
+
+	return ret
+}
+
+func (s *sdkUtilitiessrvc) Balances(ctx context.Context, chainName string, address AccountAddress) (Balances, error) {
+	q, err := s.querier(ctx, chainName, nil)
+	if err != nil {
+		return Balances{}, err
+	}
+
+	return q.Balances(ctx, address)
+}
+
+func (s *sdkUtilitiessrvc) AccountDetails(ctx context.Context, chainName string, address AccountAddress) (AccountDetails, error) {
+	q, err := s.querier(ctx, chainName, nil)
+	if err != nil {
+		return AccountDetails{}, err
+	}
+
+	return q.AccountDetails(ctx, address)
+}
+
+func (s *sdkUtilitiessrvc) ConvertAddress(address string, prefix string) (AddressInfo, error) {
+	return ConvertAddress(address, prefix, s.chains.Chains())
+}
+
 func (s *sdkUtilitiessrvc) DelegatorRewards(ctx context.Context, payload *sdkutilities.DelegatorRewardsPayload) (res *sdkutilities.DelegatorRewards2, err error) {
-	ret, err := DelegatorRewards(ctx, payload.ChainName, payload.Port, *payload.AddresHex, *payload.Bech32Prefix)
+	q, err := s.querier(ctx, payload.ChainName, payload.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := q.DelegatorRewards(ctx, payloadAddress(payload.AddresHex, payload.Bech32Prefix))
 	return &ret, err
 }
 
 func (s *sdkUtilitiessrvc) EstimateFees(ctx context.Context, payload *sdkutilities.EstimateFeesPayload) (res *sdkutilities.Simulation, err error) {
-	ret, err := FeeEstimate(ctx, payload.ChainName, payload.Port, payload.TxBytes)
+	q, err := s.querier(ctx, payload.ChainName, payload.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := q.FeeEstimate(ctx, payload.TxBytes)
 	return &ret, err
 }
 
 func (s *sdkUtilitiessrvc) StakingParams(ctx context.Context, payload *sdkutilities.StakingParamsPayload) (*sdkutilities.StakingParams2, error) {
-	ret, err := StakingParams(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+	ret, err := s.cache.Do(ctx, "stakingParams", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.StakingParams(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.StakingParams2), nil
 }
 
 func (s *sdkUtilitiessrvc) StakingPool(ctx context.Context, payload *sdkutilities.StakingPoolPayload) (*sdkutilities.StakingPool2, error) {
-	ret, err := StakingPool(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+	ret, err := s.cache.Do(ctx, "stakingPool", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.StakingPool(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.StakingPool2), nil
 }
 
 func (s *sdkUtilitiessrvc) EmoneyInflation(ctx context.Context, payload *sdkutilities.EmoneyInflationPayload) (*sdkutilities.EmoneyInflation2, error) {
-	// ret, err := EmoneyInflation(payload.ChainName, payload.Port)
-	return &sdkutilities.EmoneyInflation2{}, nil
+	ret, err := s.cache.Do(ctx, "emoneyInflation", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.EmoneyInflation(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.EmoneyInflation2), nil
 }
 
 func (s *sdkUtilitiessrvc) BudgetParams(ctx context.Context, payload *sdkutilities.BudgetParamsPayload) (*sdkutilities.BudgetParams2, error) {
-	ret, err := BudgetParams(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+	ret, err := s.cache.Do(ctx, "budgetParams", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.BudgetParams(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.BudgetParams2), nil
 }
 
 func (s *sdkUtilitiessrvc) DistributionParams(ctx context.Context, payload *sdkutilities.DistributionParamsPayload) (*sdkutilities.DistributionParams2, error) {
-	ret, err := DistributionParams(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+	ret, err := s.cache.Do(ctx, "distributionParams", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.DistributionParams(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.DistributionParams2), nil
 }
 
 func (s *sdkUtilitiessrvc) OsmoPools(ctx context.Context, payload *sdkutilities.OsmoPoolsPayload) (*sdkutilities.OsmoPools2, error) {
-	ret, err := OsmoPools(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+	ret, err := s.cache.Do(ctx, "osmoPools", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.OsmoPools(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.OsmoPools2), nil
 }
 
 func (s *sdkUtilitiessrvc) CrescentPools(ctx context.Context, payload *sdkutilities.CrescentPoolsPayload) (*sdkutilities.CrescentPools2, error) {
-	ret, err := CrescentPools(ctx, payload.ChainName, payload.Port)
-	return &ret, err
+	ret, err := s.cache.Do(ctx, "crescentPools", cacheKey(payload.ChainName, payload.Port), func() (interface{}, error) {
+		q, err := s.querier(ctx, payload.ChainName, payload.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err := q.CrescentPools(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.(*sdkutilities.CrescentPools2), nil
 }
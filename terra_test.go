@@ -0,0 +1,30 @@
+package sdkservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdkutilities "github.com/emerishq/sdk-service-meta/gen/sdk_utilities"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeTax(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/terra/tx/v1beta1/compute_tax", r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"tax_amount":[{"denom":"uluna","amount":"10"},{"denom":"uusd","amount":"20"}]}`))
+	}))
+	defer s.Close()
+
+	coins, err := computeTax(context.Background(), s.URL, []byte("tx"))
+	require.NoError(t, err)
+	require.Equal(t, []*sdkutilities.Coin{
+		{Denom: "uluna", Amount: "10"},
+		{Denom: "uusd", Amount: "20"},
+	}, coins)
+
+	_, err = computeTax(context.Background(), "", []byte("tx"))
+	require.Error(t, err)
+}
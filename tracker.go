@@ -0,0 +1,445 @@
+package sdkservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/emerishq/sdk-service-meta/gen/log"
+)
+
+// Statuses of tracked transactions.
+const (
+	TxPending  = "pending"
+	TxIncluded = "included"
+	TxFailed   = "failed"
+	TxExpired  = "expired"
+)
+
+// DefaultTrackerPollInterval is the default amount of time between two
+// lookups of a pending transaction.
+const DefaultTrackerPollInterval = 2 * time.Second
+
+// DefaultTrackerExpiry is the default amount of time after which a
+// transaction still pending is considered expired.
+const DefaultTrackerExpiry = 10 * time.Minute
+
+// DefaultTrackerRetention is the default amount of time the status of a
+// transaction is kept once it's no longer pending.
+const DefaultTrackerRetention = time.Hour
+
+// WebhookSignatureHeader is the header holding the hex encoded HMAC-SHA256
+// of webhook bodies, keyed with the webhook secret.
+const WebhookSignatureHeader = "X-Sdk-Service-Signature"
+
+const (
+	// trackerPassTimeout bounds a polling pass, transactions not looked up
+	// by then are looked up in the next pass.
+	trackerPassTimeout       = 5 * time.Second
+	trackerLookupConcurrency = 8
+
+	webhookTimeout   = 10 * time.Second
+	webhookAttempts  = 3
+	webhookQueueSize = 1024
+)
+
+// TrackerOptions holds the settings of the transaction tracker.
+type TrackerOptions struct {
+	// Enabled registers every broadcast transaction in the tracker.
+	Enabled bool
+
+	// PollInterval defaults to DefaultTrackerPollInterval.
+	PollInterval time.Duration
+
+	// Expiry defaults to DefaultTrackerExpiry.
+	Expiry time.Duration
+
+	// Retention defaults to DefaultTrackerRetention.
+	Retention time.Duration
+
+	// WebhookURL receives a POST of the TrackedTx each time the status of a
+	// transaction changes, disabled if empty.
+	WebhookURL string
+
+	// WebhookSecret keys the signature of webhook bodies, required when
+	// WebhookURL is set.
+	WebhookSecret string
+}
+
+// TrackedTx is the status of a broadcast transaction.
+type TrackedTx struct {
+	Chain       string         `json:"chain"`
+	Hash        string         `json:"hash"`
+	Status      string         `json:"status"`
+	Height      int64          `json:"height,omitempty"`
+	Code        uint32         `json:"code"`
+	Codespace   string         `json:"codespace,omitempty"`
+	Failure     *TxFailure     `json:"failure,omitempty"`
+	SubmittedAt time.Time      `json:"submitted_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	Transitions []TxTransition `json:"transitions"`
+}
+
+// TxTransition records when a transaction reached Status.
+type TxTransition struct {
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// txLookup returns the response of the transaction hash on chainName, or nil
+// if it isn't in a block yet.
+type txLookup func(ctx context.Context, chainName string, hash string) (*sdktypes.TxResponse, error)
+
+// TxTracker follows broadcast transactions until they're included in a
+// block, fail or expire, notifying a webhook of each change.
+type TxTracker struct {
+	opts     TrackerOptions
+	lookup   txLookup
+	logger   *log.Logger
+	client   *http.Client
+	webhooks chan []byte
+
+	mu  sync.Mutex
+	txs map[string]*TrackedTx
+
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// newTxTracker returns a tracker looking transactions up with lookup, and
+// starts polling them.
+func newTxTracker(opts TrackerOptions, lookup txLookup, logger *log.Logger) (*TxTracker, error) {
+	if opts.WebhookURL != "" {
+		u, err := url.Parse(opts.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook url %s", opts.WebhookURL)
+		}
+
+		if opts.WebhookSecret == "" {
+			return nil, fmt.Errorf("missing webhook secret, webhooks must be signed")
+		}
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultTrackerPollInterval
+	}
+
+	if opts.Expiry <= 0 {
+		opts.Expiry = DefaultTrackerExpiry
+	}
+
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultTrackerRetention
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	t := &TxTracker{
+		opts:     opts,
+		lookup:   lookup,
+		logger:   logger,
+		client:   &http.Client{Timeout: webhookTimeout},
+		webhooks: make(chan []byte, webhookQueueSize),
+		txs:      map[string]*TrackedTx{},
+		stop:     cancel,
+	}
+
+	t.wg.Add(2)
+	go t.poll(ctx)
+	go t.deliverWebhooks(ctx)
+
+	return t, nil
+}
+
+// trackerKey identifies transactions, chain names and hashes being case
+// insensitive.
+func trackerKey(chainName, hash string) string {
+	return strings.ToLower(chainName) + "/" + strings.ToUpper(hash)
+}
+
+// Track registers the broadcast transaction res on chainName.
+func (t *TxTracker) Track(chainName string, res BroadcastResult) {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := trackerKey(chainName, res.Hash)
+	if _, ok := t.txs[key]; ok {
+		return
+	}
+
+	tx := &TrackedTx{
+		Chain:       chainName,
+		Hash:        strings.ToUpper(res.Hash),
+		SubmittedAt: now,
+	}
+	t.txs[key] = tx
+
+	t.update(tx, res.Included, res.Height, res.Code, res.Codespace, res.Failure, now)
+}
+
+// Status returns the status of the transaction hash on chainName.
+func (t *TxTracker) Status(chainName, hash string) (TrackedTx, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tx, ok := t.txs[trackerKey(chainName, hash)]
+	if !ok {
+		return TrackedTx{}, newError(KindNotFound, fmt.Errorf("transaction %s not tracked on %s", hash, chainName))
+	}
+
+	ret := *tx
+	ret.Transitions = append([]TxTransition{}, tx.Transitions...)
+
+	return ret, nil
+}
+
+// Close stops polling transactions and delivering webhooks.
+func (t *TxTracker) Close() {
+	t.stop()
+	t.wg.Wait()
+}
+
+// update sets the status of tx from its latest known state, and queues a
+// webhook when it changed. t.mu must be held.
+func (t *TxTracker) update(tx *TrackedTx, included bool, height int64, code uint32, codespace string, failure *TxFailure, now time.Time) {
+	status := TxPending
+	switch {
+	case failure != nil:
+		status = TxFailed
+	case included:
+		status = TxIncluded
+	}
+
+	tx.Height = height
+	tx.Code = code
+	tx.Codespace = codespace
+	tx.Failure = failure
+
+	t.transition(tx, status, now)
+}
+
+// transition moves tx to status, queuing a webhook. t.mu must be held.
+func (t *TxTracker) transition(tx *TrackedTx, status string, now time.Time) {
+	if tx.Status == status {
+		return
+	}
+
+	tx.Status = status
+	tx.UpdatedAt = now
+	tx.Transitions = append(tx.Transitions, TxTransition{Status: status, At: now})
+
+	if t.opts.WebhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(tx)
+	if err != nil {
+		t.logger.Errorw("cannot marshal tracked transaction", "hash", tx.Hash, "error", err)
+		return
+	}
+
+	select {
+	case t.webhooks <- body:
+	default:
+		t.logger.Errorw("webhook queue full, dropping notification", "hash", tx.Hash, "status", status)
+	}
+}
+
+// poll looks pending transactions up every PollInterval, expires the ones
+// pending for too long and forgets the ones past their retention.
+func (t *TxTracker) poll(ctx context.Context) {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		t.checkPending(ctx)
+
+		now := time.Now()
+		t.expire(now)
+		t.prune(now)
+	}
+}
+
+// pending returns a copy of the pending transactions.
+func (t *TxTracker) pending() []TrackedTx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ret := []TrackedTx{}
+	for _, tx := range t.txs {
+		if tx.Status == TxPending {
+			ret = append(ret, *tx)
+		}
+	}
+
+	return ret
+}
+
+// checkPending looks the pending transactions up, trackerLookupConcurrency
+// at a time and for at most trackerPassTimeout.
+func (t *TxTracker) checkPending(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, trackerPassTimeout)
+	defer cancel()
+
+	sem := make(chan struct{}, trackerLookupConcurrency)
+	var wg sync.WaitGroup
+
+	for _, tx := range t.pending() {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(chainName, hash string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			t.check(ctx, chainName, hash)
+		}(tx.Chain, tx.Hash)
+	}
+
+	wg.Wait()
+}
+
+// check looks the pending transaction hash up and updates its status.
+func (t *TxTracker) check(ctx context.Context, chainName, hash string) {
+	res, err := t.lookup(ctx, chainName, hash)
+	if err != nil {
+		t.logger.Warnw("cannot look tracked transaction up", "chain", chainName, "hash", hash, "error", err)
+		return
+	}
+
+	if res == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tx, ok := t.txs[trackerKey(chainName, hash)]
+	if !ok || tx.Status != TxPending {
+		return
+	}
+
+	r := broadcastResult(res)
+	t.update(tx, r.Included, r.Height, r.Code, r.Codespace, r.Failure, time.Now())
+}
+
+// expire moves the transactions pending for longer than the expiry to
+// expired.
+func (t *TxTracker) expire(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, tx := range t.txs {
+		if tx.Status == TxPending && now.Sub(tx.SubmittedAt) > t.opts.Expiry {
+			t.transition(tx, TxExpired, now)
+		}
+	}
+}
+
+// prune forgets the transactions no longer pending for longer than the
+// retention.
+func (t *TxTracker) prune(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, tx := range t.txs {
+		if tx.Status != TxPending && now.Sub(tx.UpdatedAt) > t.opts.Retention {
+			delete(t.txs, key)
+		}
+	}
+}
+
+// deliverWebhooks posts the queued notifications in order.
+func (t *TxTracker) deliverWebhooks(ctx context.Context) {
+	defer t.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case body := <-t.webhooks:
+			if err := t.postWebhook(ctx, body); err != nil {
+				t.logger.Errorw("cannot deliver webhook", "url", t.opts.WebhookURL, "error", err)
+			}
+		}
+	}
+}
+
+// postWebhook posts body to the webhook, retrying failed deliveries.
+func (t *TxTracker) postWebhook(ctx context.Context, body []byte) error {
+	var err error
+	for attempt := 0; attempt < webhookAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+
+		if err = t.sendWebhook(ctx, body); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+func (t *TxTracker) sendWebhook(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.opts.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot create webhook request, %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, "sha256="+WebhookSignature(t.opts.WebhookSecret, body))
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot post webhook, %w", err)
+	}
+
+	_ = res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered with status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// WebhookSignature returns the hex encoded HMAC-SHA256 of body keyed with
+// secret, which receivers compare to the WebhookSignatureHeader header.
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,267 @@
+package sdkservice
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/emerishq/sdk-service-meta/gen/log"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testLogger discards the logs of tests.
+func testLogger() *log.Logger {
+	return &log.Logger{SugaredLogger: zap.NewNop().Sugar()}
+}
+
+const getTxMethod = "/cosmos.tx.v1beta1.Service/GetTx"
+
+// fakeTxService answers GetTx with the responses set by hash, and NotFound
+// for the others.
+type fakeTxService struct {
+	mu   sync.Mutex
+	txs  map[string]*sdktypes.TxResponse
+	errs map[string]error
+}
+
+func newFakeTxService() *fakeTxService {
+	return &fakeTxService{
+		txs:  map[string]*sdktypes.TxResponse{},
+		errs: map[string]error{},
+	}
+}
+
+func (f *fakeTxService) set(hash string, res *sdktypes.TxResponse, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.txs[hash] = res
+	f.errs[hash] = err
+}
+
+func (f *fakeTxService) conn() *fakeConn {
+	return newFakeConn(map[string]interface{}{
+		getTxMethod: fakeReply(func(args interface{}) (interface{}, error) {
+			hash := args.(*sdktx.GetTxRequest).Hash
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+
+			if err := f.errs[hash]; err != nil {
+				return nil, err
+			}
+
+			res, ok := f.txs[hash]
+			if !ok || res == nil {
+				return nil, status.Errorf(codes.NotFound, "tx not found: %s", hash)
+			}
+
+			return &sdktx.GetTxResponse{TxResponse: res}, nil
+		}),
+	})
+}
+
+// webhookReceiver records the notifications with a valid signature, and
+// counts the others.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	received []TrackedTx
+	invalid  int
+}
+
+func newWebhookReceiver(t *testing.T, secret string) *webhookReceiver {
+	r := &webhookReceiver{}
+
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if req.Header.Get(WebhookSignatureHeader) != "sha256="+WebhookSignature(secret, body) {
+			r.invalid++
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var tx TrackedTx
+		require.NoError(t, json.Unmarshal(body, &tx))
+
+		r.received = append(r.received, tx)
+	}))
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+// statuses returns the statuses notified so far.
+func (r *webhookReceiver) statuses() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ret := []string{}
+	for _, tx := range r.received {
+		ret = append(ret, tx.Status)
+	}
+
+	return ret
+}
+
+func newTestTracker(t *testing.T, opts TrackerOptions, txs *fakeTxService) *TxTracker {
+	q := testQuerier("cosmos-hub", SDKv44, txs.conn())
+
+	tracker, err := newTxTracker(opts, func(ctx context.Context, chainName string, hash string) (*sdktypes.TxResponse, error) {
+		return q.lookupTx(ctx, hash)
+	}, testLogger())
+	require.NoError(t, err)
+	t.Cleanup(tracker.Close)
+
+	return tracker
+}
+
+func TestTxTrackerIncluded(t *testing.T) {
+	txs := newFakeTxService()
+	hook := newWebhookReceiver(t, "secret")
+
+	tracker := newTestTracker(t, TrackerOptions{
+		Enabled:       true,
+		PollInterval:  10 * time.Millisecond,
+		WebhookURL:    hook.URL,
+		WebhookSecret: "secret",
+	}, txs)
+
+	tracker.Track("Cosmos-Hub", BroadcastResult{Hash: "abcd"})
+
+	tx, err := tracker.Status("cosmos-hub", "ABCD")
+	require.NoError(t, err)
+	require.Equal(t, TxPending, tx.Status)
+
+	txs.set("ABCD", &sdktypes.TxResponse{TxHash: "ABCD", Height: 42}, nil)
+
+	require.Eventually(t, func() bool {
+		return len(hook.statuses()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, []string{TxPending, TxIncluded}, hook.statuses())
+	require.Zero(t, hook.invalid)
+
+	tx, err = tracker.Status("COSMOS-HUB", "abcd")
+	require.NoError(t, err)
+	require.Equal(t, TxIncluded, tx.Status)
+	require.Equal(t, int64(42), tx.Height)
+	require.Len(t, tx.Transitions, 2)
+
+	_, err = tracker.Status("osmosis", "abcd")
+	require.Equal(t, KindNotFound, ClassifyError(err).Kind)
+}
+
+func TestTxTrackerFailed(t *testing.T) {
+	txs := newFakeTxService()
+	txs.set("ABCD", &sdktypes.TxResponse{TxHash: "ABCD", Height: 42, Code: 5, Codespace: "sdk", RawLog: "insufficient funds"}, nil)
+
+	tracker := newTestTracker(t, TrackerOptions{Enabled: true, PollInterval: 10 * time.Millisecond}, txs)
+	tracker.Track("cosmos-hub", BroadcastResult{Hash: "ABCD"})
+
+	require.Eventually(t, func() bool {
+		tx, err := tracker.Status("cosmos-hub", "ABCD")
+		return err == nil && tx.Status == TxFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	tx, err := tracker.Status("cosmos-hub", "ABCD")
+	require.NoError(t, err)
+	require.NotNil(t, tx.Failure)
+	require.Equal(t, uint32(5), tx.Code)
+}
+
+func TestTxTrackerExpired(t *testing.T) {
+	// Lookups failing don't keep transactions from expiring.
+	txs := newFakeTxService()
+	txs.set("ABCD", nil, status.Error(codes.Unavailable, "connection refused"))
+
+	tracker := newTestTracker(t, TrackerOptions{
+		Enabled:      true,
+		PollInterval: 10 * time.Millisecond,
+		Expiry:       50 * time.Millisecond,
+	}, txs)
+	tracker.Track("cosmos-hub", BroadcastResult{Hash: "ABCD"})
+	tracker.Track("cosmos-hub", BroadcastResult{Hash: "EF01"})
+
+	for _, hash := range []string{"ABCD", "EF01"} {
+		require.Eventually(t, func() bool {
+			tx, err := tracker.Status("cosmos-hub", hash)
+			return err == nil && tx.Status == TxExpired
+		}, 5*time.Second, 10*time.Millisecond, hash)
+	}
+}
+
+func TestTxTrackerPassBounded(t *testing.T) {
+	// Lookups hanging until their deadline don't hold the pass longer than
+	// trackerPassTimeout, nor are run more than trackerLookupConcurrency at
+	// a time.
+	var (
+		mu               sync.Mutex
+		running, maxRuns int
+	)
+
+	lookup := func(ctx context.Context, chainName string, hash string) (*sdktypes.TxResponse, error) {
+		mu.Lock()
+		running++
+		if running > maxRuns {
+			maxRuns = running
+		}
+		mu.Unlock()
+
+		<-ctx.Done()
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return nil, ctx.Err()
+	}
+
+	tracker, err := newTxTracker(TrackerOptions{Enabled: true, PollInterval: time.Hour}, lookup, testLogger())
+	require.NoError(t, err)
+	defer tracker.Close()
+
+	for i := 0; i < 3*trackerLookupConcurrency; i++ {
+		tracker.Track("cosmos-hub", BroadcastResult{Hash: strings.Repeat("A", i+1)})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	tracker.checkPending(ctx)
+
+	require.Less(t, int64(time.Since(start)), int64(trackerPassTimeout))
+	require.Equal(t, trackerLookupConcurrency, maxRuns)
+}
+
+func TestTxTrackerWebhookSettings(t *testing.T) {
+	lookup := func(ctx context.Context, chainName string, hash string) (*sdktypes.TxResponse, error) {
+		return nil, nil
+	}
+
+	for name, opts := range map[string]TrackerOptions{
+		"missing secret": {Enabled: true, WebhookURL: "https://example.com/hook"},
+		"invalid url":    {Enabled: true, WebhookURL: "example.com/hook", WebhookSecret: "secret"},
+	} {
+		_, err := newTxTracker(opts, lookup, testLogger())
+		require.Error(t, err, name)
+	}
+}
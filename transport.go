@@ -0,0 +1,181 @@
+package sdkservice
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// TLSConfig holds the transport security settings used to connect to the
+// nodes of a chain.
+// With Enabled unset connections are plaintext, otherwise the node certificate
+// is verified against the system roots, or against CAFile if set.
+// Setting both CertFile and KeyFile enables mutual TLS.
+type TLSConfig struct {
+	Enabled    bool   `yaml:"enabled" json:"enabled"`
+	CAFile     string `yaml:"ca_file" json:"ca_file"`
+	CertFile   string `yaml:"cert_file" json:"cert_file"`
+	KeyFile    string `yaml:"key_file" json:"key_file"`
+	ServerName string `yaml:"server_name" json:"server_name"`
+}
+
+// AuthConfig holds credentials sent as metadata along with every upstream
+// call, such as the API keys required by node providers.
+// Values are expanded with environment variables, so that secrets can be kept
+// out of the chain registry file.
+type AuthConfig struct {
+	BearerToken string            `yaml:"bearer_token" json:"bearer_token"`
+	Headers     map[string]string `yaml:"headers" json:"headers"`
+}
+
+func (t TLSConfig) credentials() (credentials.TransportCredentials, error) {
+	if !t.Enabled {
+		if t.CAFile != "" || t.CertFile != "" || t.KeyFile != "" {
+			return nil, fmt.Errorf("tls settings provided but tls is not enabled")
+		}
+
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: t.ServerName,
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(filepath.Clean(t.CAFile))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA bundle, %w", err)
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", t.CAFile)
+		}
+	}
+
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return nil, fmt.Errorf("both cert_file and key_file are needed for mutual tls")
+	}
+
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate, %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+func (a AuthConfig) credentials(requireTLS bool) credentials.PerRPCCredentials {
+	md := map[string]string{}
+	for k, v := range a.Headers {
+		md[k] = os.ExpandEnv(v)
+	}
+
+	if a.BearerToken != "" {
+		md["authorization"] = "Bearer " + os.ExpandEnv(a.BearerToken)
+	}
+
+	if len(md) == 0 {
+		return nil
+	}
+
+	return metadataCredentials{
+		md:         md,
+		requireTLS: requireTLS,
+	}
+}
+
+// metadataCredentials attaches a static set of metadata to each call.
+type metadataCredentials struct {
+	md         map[string]string
+	requireTLS bool
+}
+
+func (m metadataCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return m.md, nil
+}
+
+func (m metadataCredentials) RequireTransportSecurity() bool {
+	return m.requireTLS
+}
+
+// transport holds the dial options derived from a chain's TLS and auth settings.
+type transport struct {
+	creds    credentials.TransportCredentials
+	rpcCreds credentials.PerRPCCredentials
+
+	// key identifies the settings the transport was built from, so that
+	// connections aren't shared between chains with different credentials.
+	key string
+}
+
+func newTransport(t TLSConfig, a AuthConfig) (transport, error) {
+	creds, err := t.credentials()
+	if err != nil {
+		return transport{}, err
+	}
+
+	rpcCreds := a.credentials(t.Enabled)
+
+	key, err := transportKey(t, rpcCreds)
+	if err != nil {
+		return transport{}, err
+	}
+
+	return transport{
+		creds:    creds,
+		rpcCreds: rpcCreds,
+		key:      key,
+	}, nil
+}
+
+// transportKey returns a digest of the TLS settings and of the metadata sent
+// along with calls, after environment expansion.
+func transportKey(t TLSConfig, rpcCreds credentials.PerRPCCredentials) (string, error) {
+	var md map[string]string
+	if m, ok := rpcCreds.(metadataCredentials); ok {
+		md = m.md
+	}
+
+	b, err := json.Marshal(struct {
+		TLS      TLSConfig
+		Metadata map[string]string
+	}{t, md})
+	if err != nil {
+		return "", fmt.Errorf("cannot encode transport settings, %w", err)
+	}
+
+	h := sha256.Sum256(b)
+
+	return hex.EncodeToString(h[:]), nil
+}
+
+func (t transport) dialOptions() []grpc.DialOption {
+	var opts []grpc.DialOption
+
+	if t.creds != nil {
+		opts = append(opts, grpc.WithTransportCredentials(t.creds))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	if t.rpcCreds != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(t.rpcCreds))
+	}
+
+	return opts
+}
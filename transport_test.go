@@ -0,0 +1,370 @@
+package sdkservice
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/stretchr/testify/require"
+	tmp2p "github.com/tendermint/tendermint/proto/tendermint/p2p"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// testCA signs the certificates of the test servers and clients.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns a certificate for commonName, valid for localhost, and its
+// PEM encoded certificate and key.
+func (ca *testCA) issue(t *testing.T, commonName string) (tls.Certificate, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	return cert, certPEM, keyPEM
+}
+
+func writeTestFile(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+// testNode is a local gRPC server standing in for a node, answering health
+// checks and node info requests, and recording the metadata and client
+// certificates of calls.
+type testNode struct {
+	tmservice.UnimplementedServiceServer
+
+	target string
+
+	mu          sync.Mutex
+	network     string
+	md          []metadata.MD
+	clientNames []string
+}
+
+func newTestNode(t *testing.T, opts ...grpc.ServerOption) *testNode {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	n := &testNode{target: lis.Addr().String()}
+
+	opts = append(opts, grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		var clientName string
+		if p, ok := peer.FromContext(ctx); ok {
+			if ti, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(ti.State.PeerCertificates) > 0 {
+				clientName = ti.State.PeerCertificates[0].Subject.CommonName
+			}
+		}
+
+		n.mu.Lock()
+		n.md = append(n.md, md)
+		n.clientNames = append(n.clientNames, clientName)
+		n.mu.Unlock()
+
+		return handler(ctx, req)
+	}))
+
+	s := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	tmservice.RegisterServiceServer(s, n)
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	return n
+}
+
+// setNetwork sets the chain id the node reports.
+func (n *testNode) setNetwork(network string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.network = network
+}
+
+func (n *testNode) GetNodeInfo(context.Context, *tmservice.GetNodeInfoRequest) (*tmservice.GetNodeInfoResponse, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return &tmservice.GetNodeInfoResponse{
+		DefaultNodeInfo: &tmp2p.DefaultNodeInfo{Network: n.network},
+	}, nil
+}
+
+func (n *testNode) GetSyncing(context.Context, *tmservice.GetSyncingRequest) (*tmservice.GetSyncingResponse, error) {
+	return &tmservice.GetSyncingResponse{}, nil
+}
+
+func (n *testNode) GetLatestBlock(context.Context, *tmservice.GetLatestBlockRequest) (*tmservice.GetLatestBlockResponse, error) {
+	return &tmservice.GetLatestBlockResponse{
+		Block: &tmproto.Block{Header: tmproto.Header{Height: 42, Time: time.Now()}},
+	}, nil
+}
+
+// lastCall returns the metadata and client certificate name of the last
+// call received by n.
+func (n *testNode) lastCall(t *testing.T) (metadata.MD, string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	require.NotEmpty(t, n.md)
+
+	return n.md[len(n.md)-1], n.clientNames[len(n.clientNames)-1]
+}
+
+// callNode makes a health check call to target through a connection of pool
+// dialed with the transport of chain.
+func callNode(pool *ConnPool, chain Chain, target string) error {
+	conn, release, err := pool.Get(target, chain.TransportKey(), chain.DialOptions()...)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func testChain(t *testing.T, target string, tlsConfig TLSConfig, auth AuthConfig) Chain {
+	r, err := NewChainRegistry([]Chain{{
+		Name: "test",
+		GRPC: []string{target},
+		TLS:  tlsConfig,
+		Auth: auth,
+	}})
+	require.NoError(t, err)
+
+	c, err := r.Chain("test")
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestTransportPlaintext(t *testing.T) {
+	node := newTestNode(t)
+
+	os.Setenv("SDK_SERVICE_TEST_API_KEY", "secret")
+	defer os.Unsetenv("SDK_SERVICE_TEST_API_KEY")
+
+	chain := testChain(t, node.target, TLSConfig{}, AuthConfig{
+		BearerToken: "token",
+		Headers:     map[string]string{"x-api-key": "${SDK_SERVICE_TEST_API_KEY}"},
+	})
+
+	pool := NewConnPool(0)
+	defer pool.Close()
+
+	require.NoError(t, callNode(pool, chain, node.target))
+
+	md, clientName := node.lastCall(t)
+	require.Equal(t, []string{"Bearer token"}, md.Get("authorization"))
+	require.Equal(t, []string{"secret"}, md.Get("x-api-key"))
+	require.Empty(t, clientName)
+}
+
+func TestTransportCustomCA(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, _, _ := ca.issue(t, "node")
+
+	node := newTestNode(t, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		MinVersion:   tls.VersionTLS12,
+	})))
+
+	pool := NewConnPool(0)
+	defer pool.Close()
+
+	chain := testChain(t, node.target, TLSConfig{
+		Enabled:    true,
+		CAFile:     writeTestFile(t, "ca.pem", ca.pem),
+		ServerName: "localhost",
+	}, AuthConfig{BearerToken: "token"})
+
+	require.NoError(t, callNode(pool, chain, node.target))
+
+	md, _ := node.lastCall(t)
+	require.Equal(t, []string{"Bearer token"}, md.Get("authorization"))
+
+	// The node certificate isn't trusted by the system roots, nor by
+	// another CA.
+	untrusted := testChain(t, node.target, TLSConfig{Enabled: true, ServerName: "localhost"}, AuthConfig{})
+	require.Error(t, callNode(pool, untrusted, node.target))
+
+	otherCA := testChain(t, node.target, TLSConfig{
+		Enabled:    true,
+		CAFile:     writeTestFile(t, "other-ca.pem", newTestCA(t).pem),
+		ServerName: "localhost",
+	}, AuthConfig{})
+	require.Error(t, callNode(pool, otherCA, node.target))
+}
+
+func TestTransportMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, _, _ := ca.issue(t, "node")
+	_, clientCert, clientKey := ca.issue(t, "sdk-service")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	node := newTestNode(t, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})))
+
+	pool := NewConnPool(0)
+	defer pool.Close()
+
+	caFile := writeTestFile(t, "ca.pem", ca.pem)
+
+	chain := testChain(t, node.target, TLSConfig{
+		Enabled:    true,
+		CAFile:     caFile,
+		CertFile:   writeTestFile(t, "client.pem", clientCert),
+		KeyFile:    writeTestFile(t, "client-key.pem", clientKey),
+		ServerName: "localhost",
+	}, AuthConfig{})
+
+	require.NoError(t, callNode(pool, chain, node.target))
+
+	_, clientName := node.lastCall(t)
+	require.Equal(t, "sdk-service", clientName)
+
+	noClientCert := testChain(t, node.target, TLSConfig{
+		Enabled:    true,
+		CAFile:     caFile,
+		ServerName: "localhost",
+	}, AuthConfig{})
+	require.Error(t, callNode(pool, noClientCert, node.target))
+}
+
+func TestTransportInvalidSettings(t *testing.T) {
+	for name, c := range map[string]TLSConfig{
+		"tls settings without tls": {CAFile: "ca.pem"},
+		"missing key file":         {Enabled: true, CertFile: "client.pem"},
+		"missing ca file":          {Enabled: true, CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"empty ca file":            {Enabled: true, CAFile: writeTestFile(t, "ca.pem", []byte("not a certificate"))},
+	} {
+		_, err := newTransport(c, AuthConfig{})
+		require.Error(t, err, name)
+	}
+}
+
+func TestConnPoolTransportKey(t *testing.T) {
+	node := newTestNode(t)
+
+	pool := NewConnPool(0)
+	defer pool.Close()
+
+	first := testChain(t, node.target, TLSConfig{}, AuthConfig{BearerToken: "first"})
+	second := testChain(t, node.target, TLSConfig{}, AuthConfig{BearerToken: "second"})
+	require.NotEqual(t, first.TransportKey(), second.TransportKey())
+
+	// Chains sharing a node don't share each other's credentials.
+	require.NoError(t, callNode(pool, first, node.target))
+	md, _ := node.lastCall(t)
+	require.Equal(t, []string{"Bearer first"}, md.Get("authorization"))
+
+	require.NoError(t, callNode(pool, second, node.target))
+	md, _ = node.lastCall(t)
+	require.Equal(t, []string{"Bearer second"}, md.Get("authorization"))
+
+	firstConn, release, err := pool.Get(node.target, first.TransportKey(), first.DialOptions()...)
+	require.NoError(t, err)
+
+	release()
+
+	secondConn, release, err := pool.Get(node.target, second.TransportKey(), second.DialOptions()...)
+	require.NoError(t, err)
+	release()
+	require.NotSame(t, firstConn, secondConn)
+
+	// Chains with the same settings share their connections.
+	same := testChain(t, node.target, TLSConfig{}, AuthConfig{BearerToken: "first"})
+	sameConn, release, err := pool.Get(node.target, same.TransportKey(), same.DialOptions()...)
+	require.NoError(t, err)
+	release()
+	require.Same(t, firstConn, sameConn)
+}
@@ -0,0 +1,83 @@
+package sdkservice
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// FailureReason is a machine-readable reason of a failed transaction.
+type FailureReason string
+
+// Failure reasons of transactions.
+const (
+	ReasonOutOfGas          FailureReason = "out_of_gas"
+	ReasonInsufficientFunds FailureReason = "insufficient_funds"
+	ReasonInsufficientFee   FailureReason = "insufficient_fee"
+	ReasonSequenceMismatch  FailureReason = "sequence_mismatch"
+	ReasonOther             FailureReason = "other"
+)
+
+// sdkFailureReasons maps the codes of the "sdk" codespace, as defined in
+// cosmos-sdk types/errors, to failure reasons.
+var sdkFailureReasons = map[uint32]FailureReason{
+	5:  ReasonInsufficientFunds,
+	11: ReasonOutOfGas,
+	13: ReasonInsufficientFee,
+	32: ReasonSequenceMismatch,
+}
+
+var (
+	// sequenceMismatchLog matches the log of ErrWrongSequence, like
+	// "account sequence mismatch, expected 5, got 4".
+	sequenceMismatchLog = regexp.MustCompile(`expected (\d+), got (\d+)`)
+
+	// insufficientFeeLog matches the log of ErrInsufficientFee, like
+	// "insufficient fees; got: 10uatom required: 500uatom".
+	insufficientFeeLog = regexp.MustCompile(`required: ([^\s:]+)`)
+)
+
+// TxFailure details why a transaction failed.
+type TxFailure struct {
+	Reason FailureReason `json:"reason"`
+
+	// ExpectedSequence and GotSequence are set for sequence mismatches.
+	ExpectedSequence *uint64 `json:"expected_sequence,omitempty"`
+	GotSequence      *uint64 `json:"got_sequence,omitempty"`
+
+	// RequiredFee is set for insufficient fees, when the node reports it.
+	RequiredFee string `json:"required_fee,omitempty"`
+}
+
+// classifyFailure returns the details of a transaction which failed with
+// the given codespace, code and raw log.
+func classifyFailure(codespace string, code uint32, rawLog string) *TxFailure {
+	ret := &TxFailure{
+		Reason: ReasonOther,
+	}
+
+	if codespace != "sdk" {
+		return ret
+	}
+
+	if r, ok := sdkFailureReasons[code]; ok {
+		ret.Reason = r
+	}
+
+	switch ret.Reason {
+	case ReasonSequenceMismatch:
+		if m := sequenceMismatchLog.FindStringSubmatch(rawLog); m != nil {
+			expected, errExpected := strconv.ParseUint(m[1], 10, 64)
+			got, errGot := strconv.ParseUint(m[2], 10, 64)
+			if errExpected == nil && errGot == nil {
+				ret.ExpectedSequence = &expected
+				ret.GotSequence = &got
+			}
+		}
+	case ReasonInsufficientFee:
+		if m := insufficientFeeLog.FindStringSubmatch(rawLog); m != nil {
+			ret.RequiredFee = m[1]
+		}
+	}
+
+	return ret
+}
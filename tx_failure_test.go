@@ -0,0 +1,88 @@
+package sdkservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	seq := func(n uint64) *uint64 { return &n }
+
+	// Raw logs as returned by SDK nodes.
+	tests := []struct {
+		name      string
+		codespace string
+		code      uint32
+		rawLog    string
+		want      TxFailure
+	}{
+		{
+			name:      "out of gas",
+			codespace: "sdk",
+			code:      11,
+			rawLog:    "out of gas in location: WriteFlat; gasWanted: 200000, gasUsed: 200523: out of gas",
+			want:      TxFailure{Reason: ReasonOutOfGas},
+		},
+		{
+			name:      "insufficient funds",
+			codespace: "sdk",
+			code:      5,
+			rawLog:    "failed to execute message; message index: 0: 100uatom is smaller than 1000uatom: insufficient funds",
+			want:      TxFailure{Reason: ReasonInsufficientFunds},
+		},
+		{
+			name:      "insufficient fee",
+			codespace: "sdk",
+			code:      13,
+			rawLog:    "insufficient fees; got: 10uatom required: 500uatom: insufficient fee",
+			want:      TxFailure{Reason: ReasonInsufficientFee, RequiredFee: "500uatom"},
+		},
+		{
+			name:      "insufficient fee without fee",
+			codespace: "sdk",
+			code:      13,
+			rawLog:    "insufficient fees; got:  required: 500uatom,20ustake: insufficient fee",
+			want:      TxFailure{Reason: ReasonInsufficientFee, RequiredFee: "500uatom,20ustake"},
+		},
+		{
+			name:      "insufficient fee without required fee",
+			codespace: "sdk",
+			code:      13,
+			rawLog:    "insufficient fee",
+			want:      TxFailure{Reason: ReasonInsufficientFee},
+		},
+		{
+			name:      "sequence mismatch",
+			codespace: "sdk",
+			code:      32,
+			rawLog:    "account sequence mismatch, expected 5, got 4: incorrect account sequence",
+			want:      TxFailure{Reason: ReasonSequenceMismatch, ExpectedSequence: seq(5), GotSequence: seq(4)},
+		},
+		{
+			name:      "sequence mismatch without sequences",
+			codespace: "sdk",
+			code:      32,
+			rawLog:    "incorrect account sequence",
+			want:      TxFailure{Reason: ReasonSequenceMismatch},
+		},
+		{
+			name:      "unknown code",
+			codespace: "sdk",
+			code:      4,
+			rawLog:    "signature verification failed; please verify account number (0) and chain-id (cosmoshub-4): unauthorized",
+			want:      TxFailure{Reason: ReasonOther},
+		},
+		{
+			name:      "unknown codespace",
+			codespace: "wasm",
+			code:      5,
+			rawLog:    "Error calling the VM: Error executing Wasm: execute wasm contract failed",
+			want:      TxFailure{Reason: ReasonOther},
+		},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, &tt.want, classifyFailure(tt.codespace, tt.code, tt.rawLog), tt.name)
+	}
+}
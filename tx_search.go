@@ -0,0 +1,262 @@
+package sdkservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	sdkquery "github.com/cosmos/cosmos-sdk/types/query"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// Transaction search orderings.
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// DefaultTxSearchLimit is the number of transactions returned per page when
+// no limit is given.
+const DefaultTxSearchLimit = 30
+
+// MaxTxSearchLimit is the largest number of transactions returned per page.
+const MaxTxSearchLimit = 100
+
+var txSearchOrders = map[string]sdktx.OrderBy{
+	OrderAsc:  sdktx.OrderBy_ORDER_BY_ASC,
+	OrderDesc: sdktx.OrderBy_ORDER_BY_DESC,
+}
+
+// TxSearch is a search of transactions by the events they emitted. All the
+// conditions must match.
+type TxSearch struct {
+	// Events are Tendermint event conditions, like message.module='bank',
+	// tx.height>=100 or message.memo EXISTS.
+	Events []string
+
+	// Sender, Recipient and Action match message.sender, transfer.recipient
+	// and message.action.
+	Sender    string
+	Recipient string
+	Action    string
+
+	// MinHeight and MaxHeight bound the height of the transactions, unset if
+	// zero.
+	MinHeight int64
+	MaxHeight int64
+
+	// Page starts at 1, which is the default.
+	Page uint64
+
+	// Limit defaults to DefaultTxSearchLimit.
+	Limit uint64
+
+	// Order is OrderAsc or OrderDesc, the default, by height.
+	Order string
+}
+
+// TxSearchResult is a page of transactions, with Txs and TxResponses in
+// canonical proto-JSON.
+type TxSearchResult struct {
+	Page        uint64          `json:"page"`
+	Limit       uint64          `json:"limit"`
+	Total       uint64          `json:"total"`
+	Txs         json.RawMessage `json:"txs"`
+	TxResponses json.RawMessage `json:"tx_responses"`
+}
+
+// events returns the conditions of s, in the format of the tx service.
+func (s TxSearch) events() ([]string, error) {
+	ret := []string{}
+
+	for _, e := range s.Events {
+		c, err := parseEventCondition(e)
+		if err != nil {
+			return nil, newError(KindInvalidArgument, fmt.Errorf("invalid event %q, %w", e, err))
+		}
+
+		ret = append(ret, c)
+	}
+
+	for _, c := range []struct {
+		key   string
+		value string
+	}{
+		{"message.sender", s.Sender},
+		{"transfer.recipient", s.Recipient},
+		{"message.action", s.Action},
+	} {
+		if c.value == "" {
+			continue
+		}
+
+		if strings.ContainsAny(c.value, `'"`) {
+			return nil, newError(KindInvalidArgument, fmt.Errorf("invalid %s value %q", c.key, c.value))
+		}
+
+		ret = append(ret, fmt.Sprintf("%s='%s'", c.key, c.value))
+	}
+
+	if s.MinHeight < 0 || s.MaxHeight < 0 || (s.MaxHeight > 0 && s.MinHeight > s.MaxHeight) {
+		return nil, newError(KindInvalidArgument, fmt.Errorf("invalid height range %d-%d", s.MinHeight, s.MaxHeight))
+	}
+
+	if s.MinHeight > 0 {
+		ret = append(ret, fmt.Sprintf("tx.height>=%d", s.MinHeight))
+	}
+
+	if s.MaxHeight > 0 {
+		ret = append(ret, fmt.Sprintf("tx.height<=%d", s.MaxHeight))
+	}
+
+	if len(ret) == 0 {
+		return nil, newError(KindInvalidArgument, fmt.Errorf("at least one search condition is required"))
+	}
+
+	return ret, nil
+}
+
+// eventOperators are the operators of Tendermint event queries, the ones
+// prefixing others first.
+var eventOperators = []string{"<=", ">=", "=", "<", ">", "CONTAINS", "EXISTS"}
+
+var (
+	eventKeyRegex    = regexp.MustCompile(`^[^\s=<>'"()]+`)
+	eventNumberRegex = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+)
+
+// parseEventCondition parses e as a Tendermint event condition, like
+// type.attribute='value', tx.height>=100 or type.attribute EXISTS, returning
+// it in canonical form.
+func parseEventCondition(e string) (string, error) {
+	e = strings.TrimSpace(e)
+
+	key := eventKeyRegex.FindString(e)
+	if key == "" {
+		return "", fmt.Errorf("missing key, must be like type.attribute='value'")
+	}
+
+	rest := strings.TrimLeft(e[len(key):], " \t")
+
+	op := ""
+	for _, o := range eventOperators {
+		if strings.HasPrefix(rest, o) {
+			op = o
+			break
+		}
+	}
+
+	if op == "" {
+		return "", fmt.Errorf("missing operator after %s, must be one of %s", key, strings.Join(eventOperators, ", "))
+	}
+
+	value := strings.TrimSpace(rest[len(op):])
+
+	if op == "EXISTS" {
+		if value != "" {
+			return "", fmt.Errorf("EXISTS takes no value")
+		}
+
+		return key + " EXISTS", nil
+	}
+
+	quoted := len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\''
+	switch {
+	case quoted:
+		if strings.Contains(value[1:len(value)-1], "'") {
+			return "", fmt.Errorf("value %s has an unescaped quote", value)
+		}
+	case op == "CONTAINS":
+		return "", fmt.Errorf("CONTAINS takes a quoted value")
+	case eventNumberRegex.MatchString(value):
+	case strings.HasPrefix(value, "TIME "):
+		if _, err := time.Parse(time.RFC3339, strings.TrimPrefix(value, "TIME ")); err != nil {
+			return "", fmt.Errorf("invalid time %s, must be RFC3339", value)
+		}
+	case strings.HasPrefix(value, "DATE "):
+		if _, err := time.Parse("2006-01-02", strings.TrimPrefix(value, "DATE ")); err != nil {
+			return "", fmt.Errorf("invalid date %s, must be like 2006-01-02", value)
+		}
+	default:
+		return "", fmt.Errorf("invalid value %q, must be a quoted string, a number, a TIME or a DATE", value)
+	}
+
+	if op == "CONTAINS" {
+		return key + " CONTAINS " + value, nil
+	}
+
+	return key + op + value, nil
+}
+
+// validate checks s and applies its defaults.
+func (s *TxSearch) validate() error {
+	if s.Page == 0 {
+		s.Page = 1
+	}
+
+	switch {
+	case s.Limit == 0:
+		s.Limit = DefaultTxSearchLimit
+	case s.Limit > MaxTxSearchLimit:
+		return newError(KindInvalidArgument, fmt.Errorf("limit must be at most %d", MaxTxSearchLimit))
+	}
+
+	if s.Order == "" {
+		s.Order = OrderDesc
+	}
+
+	if _, ok := txSearchOrders[s.Order]; !ok {
+		return newError(KindInvalidArgument, fmt.Errorf("unknown order %q", s.Order))
+	}
+
+	return nil
+}
+
+// SearchTxs returns the page of transactions matching search.
+func (q *chainQuerier) SearchTxs(ctx context.Context, search TxSearch) (TxSearchResult, error) {
+	if err := search.validate(); err != nil {
+		return TxSearchResult{}, err
+	}
+
+	events, err := search.events()
+	if err != nil {
+		return TxSearchResult{}, err
+	}
+
+	txClient := sdktx.NewServiceClient(q.conn)
+
+	grpcRes, err := txClient.GetTxsEvent(ctx, &sdktx.GetTxsEventRequest{
+		Events: events,
+		Pagination: &sdkquery.PageRequest{
+			Offset: (search.Page - 1) * search.Limit,
+			Limit:  search.Limit,
+		},
+		OrderBy: txSearchOrders[search.Order],
+	})
+	if err != nil {
+		return TxSearchResult{}, queryError("tx", err)
+	}
+
+	resJSON, err := q.marshalJSON(ctx, grpcRes)
+	if err != nil {
+		return TxSearchResult{}, err
+	}
+
+	ret := TxSearchResult{
+		Page:  search.Page,
+		Limit: search.Limit,
+	}
+
+	if err := json.Unmarshal(resJSON, &ret); err != nil {
+		return TxSearchResult{}, fmt.Errorf("cannot json unmarshal transactions, %w", err)
+	}
+
+	if grpcRes.Pagination != nil {
+		ret.Total = grpcRes.Pagination.Total
+	}
+
+	return ret, nil
+}
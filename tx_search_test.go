@@ -0,0 +1,61 @@
+package sdkservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEventCondition(t *testing.T) {
+	for e, want := range map[string]string{
+		"message.module='bank'":                   "message.module='bank'",
+		" message.module = 'bank' ":               "message.module='bank'",
+		"tx.height=5":                             "tx.height=5",
+		"tx.height<5":                             "tx.height<5",
+		"tx.height <= 5":                          "tx.height<=5",
+		"tx.height>5":                             "tx.height>5",
+		"tx.height>=5":                            "tx.height>=5",
+		"transfer.amount>1.5":                     "transfer.amount>1.5",
+		"message.action CONTAINS 'delegate'":      "message.action CONTAINS 'delegate'",
+		"message.sender EXISTS":                   "message.sender EXISTS",
+		"block.time>=TIME 2022-04-15T05:20:00Z":   "block.time>=TIME 2022-04-15T05:20:00Z",
+		"block.date<DATE 2022-04-15":              "block.date<DATE 2022-04-15",
+		"wasm.memo='a=b'":                         "wasm.memo='a=b'",
+		"ibc_transfer.receiver='cosmos1abc<>def'": "ibc_transfer.receiver='cosmos1abc<>def'",
+	} {
+		got, err := parseEventCondition(e)
+		require.NoError(t, err, e)
+		require.Equal(t, want, got, e)
+	}
+
+	for _, e := range []string{
+		"",
+		"='bank'",
+		"message.module",
+		"message.module 'bank'",
+		"message.module=bank",
+		"message.module='ba'nk'",
+		"message.module='bank' AND tx.height=5",
+		"message.sender EXISTS 'a'",
+		"tx.height CONTAINS 5",
+		"tx.height=>5",
+		"block.time>=TIME yesterday",
+		"block.date<DATE 15/04/2022",
+	} {
+		_, err := parseEventCondition(e)
+		require.Error(t, err, e)
+	}
+}
+
+func TestTxSearchEvents(t *testing.T) {
+	events, err := TxSearch{
+		Events:    []string{"message.module = 'bank'", "tx.height<100"},
+		Sender:    "cosmos1sender",
+		MinHeight: 10,
+	}.events()
+	require.NoError(t, err)
+	require.Equal(t, []string{"message.module='bank'", "tx.height<100", "message.sender='cosmos1sender'", "tx.height>=10"}, events)
+
+	_, err = TxSearch{Events: []string{"message.module"}}.events()
+	require.Equal(t, KindInvalidArgument, ClassifyError(err).Kind)
+}
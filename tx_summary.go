@@ -0,0 +1,344 @@
+package sdkservice
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distribution "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
+	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibctransfer "github.com/cosmos/ibc-go/v2/modules/apps/transfer/types"
+	sdkutilities "github.com/emerishq/sdk-service-meta/gen/sdk_utilities"
+)
+
+// TxSummary is the decoded content of a transaction.
+type TxSummary struct {
+	Messages      []MsgSummary `json:"messages"`
+	Memo          string       `json:"memo"`
+	TimeoutHeight uint64       `json:"timeout_height"`
+	Fee           []Coin       `json:"fee"`
+	GasLimit      uint64       `json:"gas_limit"`
+	FeePayer      string       `json:"fee_payer,omitempty"`
+	FeeGranter    string       `json:"fee_granter,omitempty"`
+	Signers       []SignerInfo `json:"signers"`
+}
+
+// MsgSummary summarizes a transaction message. Only Type is set for
+// messages which aren't well-known.
+type MsgSummary struct {
+	// Type is the type URL of the message.
+	Type string `json:"type"`
+
+	// Sender is the account sending funds or acting, like the delegator or
+	// the voter. It's empty for multi-sends with several inputs.
+	Sender string `json:"sender,omitempty"`
+
+	// Inputs are the senders of multi-sends.
+	Inputs []Transfer `json:"inputs,omitempty"`
+
+	// Recipients are the accounts receiving funds, and their part of them.
+	Recipients []Transfer `json:"recipients,omitempty"`
+
+	// Amount is the total amount sent, delegated or undelegated.
+	Amount []Coin `json:"amount,omitempty"`
+
+	Validator            string `json:"validator,omitempty"`
+	DestinationValidator string `json:"destination_validator,omitempty"`
+
+	ProposalID uint64 `json:"proposal_id,omitempty"`
+	VoteOption string `json:"vote_option,omitempty"`
+
+	// IBC holds the routing of IBC transfers.
+	IBC *IBCRouting `json:"ibc,omitempty"`
+
+	// Messages are the messages executed on behalf of the granter by
+	// authz executions.
+	Messages []MsgSummary `json:"messages,omitempty"`
+}
+
+// Transfer is an amount sent by or to Address.
+type Transfer struct {
+	Address string `json:"address"`
+	Amount  []Coin `json:"amount"`
+}
+
+// IBCRouting holds the source and timeout of an IBC transfer.
+type IBCRouting struct {
+	SourcePort            string `json:"source_port"`
+	SourceChannel         string `json:"source_channel"`
+	TimeoutRevisionNumber uint64 `json:"timeout_revision_number"`
+	TimeoutRevisionHeight uint64 `json:"timeout_revision_height"`
+	TimeoutTimestampNanos uint64 `json:"timeout_timestamp"`
+}
+
+// SignerInfo describes a signer of a transaction.
+type SignerInfo struct {
+	// PublicKeyType is the type URL of the public key, PublicKey and
+	// AddressHex are only set for key types the codec knows.
+	PublicKeyType string `json:"public_key_type"`
+	PublicKey     []byte `json:"public_key,omitempty"`
+	AddressHex    string `json:"address_hex,omitempty"`
+	Sequence      uint64 `json:"sequence"`
+	SignMode      string `json:"sign_mode"`
+}
+
+// Coin is an amount of denom.
+type Coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// coins returns c as is, coins of transactions sent by clients may be
+// invalid, with negative or missing amounts.
+func coins(c sdktypes.Coins) []Coin {
+	ret := make([]Coin, 0, len(c))
+	for _, coin := range c {
+		amount := ""
+		if !coin.Amount.IsNil() {
+			amount = coin.Amount.String()
+		}
+
+		ret = append(ret, Coin{
+			Denom:  coin.Denom,
+			Amount: amount,
+		})
+	}
+
+	return ret
+}
+
+// validCoins returns whether c is a sorted set of positive coins with valid
+// denoms, which the cosmos-sdk coin arithmetic requires not to panic.
+func validCoins(c sdktypes.Coins) bool {
+	for _, coin := range c {
+		if coin.Amount.IsNil() {
+			return false
+		}
+	}
+
+	return c.Validate() == nil
+}
+
+// decodeTx decodes txBytes without unpacking its messages, so that messages
+// of modules the codec doesn't know don't fail the whole transaction.
+func decodeTx(txBytes []byte) (sdktx.Tx, error) {
+	txObj := sdktx.Tx{}
+
+	if err := txObj.Unmarshal(txBytes); err != nil {
+		return sdktx.Tx{}, newError(KindInvalidArgument, fmt.Errorf("cannot unmarshal transaction, %w", err))
+	}
+
+	if txObj.Body == nil || txObj.AuthInfo == nil {
+		return sdktx.Tx{}, newError(KindInvalidArgument, fmt.Errorf("transaction has no body or auth info"))
+	}
+
+	return txObj, nil
+}
+
+// summarizeTx decodes txBytes with cdc, summarizing its messages, fee and
+// signers.
+func summarizeTx(cdc codec.Codec, txBytes []byte) (TxSummary, error) {
+	txObj, err := decodeTx(txBytes)
+	if err != nil {
+		return TxSummary{}, err
+	}
+
+	ret := TxSummary{
+		Messages:      summarizeMsgs(cdc, txObj.Body.Messages),
+		Memo:          txObj.Body.Memo,
+		TimeoutHeight: txObj.Body.TimeoutHeight,
+		Fee:           []Coin{},
+		Signers:       []SignerInfo{},
+	}
+
+	if fee := txObj.AuthInfo.Fee; fee != nil {
+		ret.Fee = coins(fee.Amount)
+		ret.GasLimit = fee.GasLimit
+		ret.FeePayer = fee.Payer
+		ret.FeeGranter = fee.Granter
+	}
+
+	for _, si := range txObj.AuthInfo.SignerInfos {
+		ret.Signers = append(ret.Signers, summarizeSigner(cdc, si))
+	}
+
+	return ret, nil
+}
+
+func summarizeSigner(cdc codec.Codec, si *sdktx.SignerInfo) SignerInfo {
+	ret := SignerInfo{
+		Sequence: si.Sequence,
+	}
+
+	switch {
+	case si.ModeInfo.GetSingle() != nil:
+		ret.SignMode = si.ModeInfo.GetSingle().Mode.String()
+	case si.ModeInfo.GetMulti() != nil:
+		ret.SignMode = "multi"
+	}
+
+	if si.PublicKey == nil {
+		return ret
+	}
+
+	ret.PublicKeyType = si.PublicKey.TypeUrl
+
+	var pk cryptotypes.PubKey
+	if err := cdc.UnpackAny(si.PublicKey, &pk); err == nil {
+		ret.PublicKey = pk.Bytes()
+		ret.AddressHex = keyAddress(pk)
+	}
+
+	return ret
+}
+
+// keyAddress returns the hex address of pk, or an empty string if pk is
+// malformed. The key types of the cosmos-sdk panic on keys of the wrong
+// size, which clients can send.
+func keyAddress(pk cryptotypes.PubKey) (addr string) {
+	defer func() {
+		if recover() != nil {
+			addr = ""
+		}
+	}()
+
+	return hex.EncodeToString(pk.Address())
+}
+
+func summarizeMsgs(cdc codec.Codec, msgs []*codectypes.Any) []MsgSummary {
+	ret := make([]MsgSummary, 0, len(msgs))
+	for _, m := range msgs {
+		ret = append(ret, summarizeMsg(cdc, m))
+	}
+
+	return ret
+}
+
+// summarizeMsg summarizes the message packed in m, falling back to its type
+// URL if the codec doesn't know it.
+func summarizeMsg(cdc codec.Codec, m *codectypes.Any) MsgSummary {
+	ret := MsgSummary{
+		Type: m.TypeUrl,
+	}
+
+	var msg sdktypes.Msg
+	if err := cdc.UnpackAny(m, &msg); err != nil {
+		return ret
+	}
+
+	switch mt := msg.(type) {
+	case *bank.MsgSend:
+		ret.Sender = mt.FromAddress
+		ret.Recipients = []Transfer{{Address: mt.ToAddress, Amount: coins(mt.Amount)}}
+		ret.Amount = coins(mt.Amount)
+	case *bank.MsgMultiSend:
+		// The total is left out if any input is invalid.
+		total, valid := sdktypes.NewCoins(), true
+		for _, in := range mt.Inputs {
+			ret.Inputs = append(ret.Inputs, Transfer{Address: in.Address, Amount: coins(in.Coins)})
+
+			valid = valid && validCoins(in.Coins)
+			if valid {
+				total = total.Add(in.Coins...)
+			}
+		}
+		for _, out := range mt.Outputs {
+			ret.Recipients = append(ret.Recipients, Transfer{Address: out.Address, Amount: coins(out.Coins)})
+		}
+		if len(mt.Inputs) == 1 {
+			ret.Sender = mt.Inputs[0].Address
+		}
+		if valid {
+			ret.Amount = coins(total)
+		}
+	case *ibctransfer.MsgTransfer:
+		ret.Sender = mt.Sender
+		ret.Recipients = []Transfer{{Address: mt.Receiver, Amount: coins(sdktypes.Coins{mt.Token})}}
+		ret.Amount = coins(sdktypes.Coins{mt.Token})
+		ret.IBC = &IBCRouting{
+			SourcePort:            mt.SourcePort,
+			SourceChannel:         mt.SourceChannel,
+			TimeoutRevisionNumber: mt.TimeoutHeight.RevisionNumber,
+			TimeoutRevisionHeight: mt.TimeoutHeight.RevisionHeight,
+			TimeoutTimestampNanos: mt.TimeoutTimestamp,
+		}
+	case *staking.MsgDelegate:
+		ret.Sender = mt.DelegatorAddress
+		ret.Validator = mt.ValidatorAddress
+		ret.Amount = coins(sdktypes.Coins{mt.Amount})
+	case *staking.MsgUndelegate:
+		ret.Sender = mt.DelegatorAddress
+		ret.Validator = mt.ValidatorAddress
+		ret.Amount = coins(sdktypes.Coins{mt.Amount})
+	case *staking.MsgBeginRedelegate:
+		ret.Sender = mt.DelegatorAddress
+		ret.Validator = mt.ValidatorSrcAddress
+		ret.DestinationValidator = mt.ValidatorDstAddress
+		ret.Amount = coins(sdktypes.Coins{mt.Amount})
+	case *distribution.MsgWithdrawDelegatorReward:
+		ret.Sender = mt.DelegatorAddress
+		ret.Validator = mt.ValidatorAddress
+	case *gov.MsgVote:
+		ret.Sender = mt.Voter
+		ret.ProposalID = mt.ProposalId
+		ret.VoteOption = mt.Option.String()
+	case *authz.MsgExec:
+		ret.Sender = mt.Grantee
+		ret.Messages = summarizeMsgs(cdc, mt.Msgs)
+	case *MsgEthereumTx:
+		ret.Sender = mt.From
+	}
+
+	return ret
+}
+
+// txMetadata decodes txBytes with cdc, returning the type of its messages
+// and the details of IBC transfers.
+func txMetadata(cdc codec.Codec, txBytes []byte) (sdkutilities.TxMessagesMetadata, error) {
+	txObj, err := decodeTx(txBytes)
+	if err != nil {
+		return sdkutilities.TxMessagesMetadata{}, err
+	}
+
+	ret := sdkutilities.TxMessagesMetadata{
+		MessagesMetadata: []*sdkutilities.MsgMetadata{},
+	}
+
+	for _, m := range txObj.Body.Messages {
+		txm := &sdkutilities.MsgMetadata{
+			MsgType: m.TypeUrl,
+		}
+
+		var msg sdktypes.Msg
+		if err := cdc.UnpackAny(m, &msg); err == nil {
+			if mt, ok := msg.(*ibctransfer.MsgTransfer); ok {
+				txm.IbcTransferMetadata = &sdkutilities.IBCTransferMetadata{
+					SourcePort:    &mt.SourcePort,
+					SourceChannel: &mt.SourceChannel,
+					Token: &sdkutilities.Coin{
+						Denom:  mt.Token.Denom,
+						Amount: mt.Token.Amount.String(),
+					},
+					Sender:   &mt.Sender,
+					Receiver: &mt.Receiver,
+					TimeoutHeight: &sdkutilities.IBCHeight{
+						RevisionNumber: &mt.TimeoutHeight.RevisionNumber,
+						RevisionHeight: &mt.TimeoutHeight.RevisionHeight,
+					},
+					TiemoutTimestamp: &mt.TimeoutTimestamp,
+				}
+			}
+		}
+
+		ret.MessagesMetadata = append(ret.MessagesMetadata, txm)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,118 @@
+package sdkservice
+
+import (
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
+	ibctransfer "github.com/cosmos/ibc-go/v2/modules/apps/transfer/types"
+	gogoproto "github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// badCoin is a coin the cosmos-sdk refuses to build, with a negative amount
+// and an invalid denom.
+var badCoin = sdktypes.Coin{Denom: "!!", Amount: sdktypes.NewInt(-5)}
+
+// noAmountCoin returns the encoding of a coin without amount, which decodes
+// to a nil Int.
+func noAmountCoin(denom string) []byte {
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendString(b, denom)
+}
+
+func testTx(t *testing.T, msgs []*codectypes.Any, signers ...*sdktx.SignerInfo) []byte {
+	cdc := sdkAdapters[defaultSDKVersion].Codec()
+
+	txBytes, err := cdc.Marshal(&sdktx.Tx{
+		Body:     &sdktx.TxBody{Messages: msgs},
+		AuthInfo: &sdktx.AuthInfo{Fee: &sdktx.Fee{}, SignerInfos: signers},
+	})
+	require.NoError(t, err)
+
+	return txBytes
+}
+
+func testAny(t *testing.T, msg gogoproto.Message) *codectypes.Any {
+	a, err := codectypes.NewAnyWithValue(msg)
+	require.NoError(t, err)
+
+	return a
+}
+
+func TestSummarizeTxMalformedCoins(t *testing.T) {
+	// A delegation whose amount has a denom but no amount.
+	var delegate []byte
+	delegate = protowire.AppendTag(delegate, 1, protowire.BytesType)
+	delegate = protowire.AppendString(delegate, "cosmos1delegator")
+	delegate = protowire.AppendTag(delegate, 2, protowire.BytesType)
+	delegate = protowire.AppendString(delegate, "cosmosvaloper1validator")
+	delegate = protowire.AppendTag(delegate, 3, protowire.BytesType)
+	delegate = protowire.AppendBytes(delegate, noAmountCoin("uatom"))
+
+	msgs := []*codectypes.Any{
+		{TypeUrl: "/cosmos.staking.v1beta1.MsgDelegate", Value: delegate},
+		testAny(t, &staking.MsgUndelegate{DelegatorAddress: "d", ValidatorAddress: "v", Amount: badCoin}),
+		testAny(t, &staking.MsgBeginRedelegate{DelegatorAddress: "d", ValidatorSrcAddress: "v1", ValidatorDstAddress: "v2", Amount: badCoin}),
+		testAny(t, &ibctransfer.MsgTransfer{SourcePort: "transfer", SourceChannel: "channel-0", Sender: "s", Receiver: "r", Token: badCoin}),
+		testAny(t, &bank.MsgMultiSend{
+			Inputs: []bank.Input{
+				// Unsorted coins make the cosmos-sdk coin addition panic.
+				{Address: "a", Coins: sdktypes.Coins{sdktypes.NewInt64Coin("uosmo", 1), sdktypes.NewInt64Coin("uatom", 1)}},
+			},
+			Outputs: []bank.Output{{Address: "b", Coins: sdktypes.Coins{badCoin}}},
+		}),
+	}
+
+	// A secp256k1 key of the wrong size, whose address would panic.
+	pk, err := codectypes.NewAnyWithValue(&secp256k1.PubKey{Key: []byte{1, 2, 3}})
+	require.NoError(t, err)
+
+	txBytes := testTx(t, msgs, &sdktx.SignerInfo{PublicKey: pk})
+
+	var summary TxSummary
+	require.NotPanics(t, func() {
+		summary, err = summarizeTx(sdkAdapters[defaultSDKVersion].Codec(), txBytes)
+	})
+	require.NoError(t, err)
+	require.Len(t, summary.Messages, 5)
+
+	require.Equal(t, "cosmos1delegator", summary.Messages[0].Sender)
+	require.Equal(t, []Coin{{Denom: "uatom", Amount: ""}}, summary.Messages[0].Amount)
+
+	for _, m := range summary.Messages[1:4] {
+		require.Equal(t, []Coin{{Denom: "!!", Amount: "-5"}}, m.Amount, m.Type)
+	}
+
+	require.Equal(t, "channel-0", summary.Messages[3].IBC.SourceChannel)
+
+	multiSend := summary.Messages[4]
+	require.Len(t, multiSend.Inputs, 1)
+	require.Nil(t, multiSend.Amount)
+	require.Equal(t, []Coin{{Denom: "!!", Amount: "-5"}}, multiSend.Recipients[0].Amount)
+
+	require.Len(t, summary.Signers, 1)
+	require.Equal(t, []byte{1, 2, 3}, summary.Signers[0].PublicKey)
+	require.Empty(t, summary.Signers[0].AddressHex)
+}
+
+func TestSummarizeTxMultiSendTotal(t *testing.T) {
+	txBytes := testTx(t, []*codectypes.Any{
+		testAny(t, &bank.MsgMultiSend{
+			Inputs: []bank.Input{
+				{Address: "a", Coins: sdktypes.NewCoins(sdktypes.NewInt64Coin("uatom", 1))},
+				{Address: "b", Coins: sdktypes.NewCoins(sdktypes.NewInt64Coin("uatom", 2), sdktypes.NewInt64Coin("uosmo", 3))},
+			},
+		}),
+	})
+
+	summary, err := summarizeTx(sdkAdapters[defaultSDKVersion].Codec(), txBytes)
+	require.NoError(t, err)
+	require.Equal(t, []Coin{{Denom: "uatom", Amount: "3"}, {Denom: "uosmo", Amount: "3"}}, summary.Messages[0].Amount)
+	require.Empty(t, summary.Messages[0].Sender)
+}
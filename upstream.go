@@ -0,0 +1,454 @@
+package sdkservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// Node selection policies, used when a chain has more than one gRPC endpoint.
+const (
+	RoundRobin   = "round_robin"
+	LeastLatency = "least_latency"
+)
+
+// DefaultEjectionTime is the amount of time a node is kept out of rotation
+// after failing a call.
+const DefaultEjectionTime = 30 * time.Second
+
+// latencyDecay is the weight given to the latest sample in the latency
+// moving average.
+const latencyDecay = 0.3
+
+// nonIdempotentMethods lists the upstream methods which must not be retried
+// on another node once they've been sent.
+var nonIdempotentMethods = map[string]bool{
+	"/cosmos.tx.v1beta1.Service/BroadcastTx": true,
+}
+
+// errNoNodes is returned when none of the nodes of a chain can be used.
+var errNoNodes = errors.New("no usable node")
+
+// Upstream routes calls to the nodes of the configured chains, spreading them
+// according to each chain balancing policy and failing over to another node
+// when one becomes unavailable.
+type Upstream struct {
+	pool    *ConnPool
+	flights *flightGroup
+
+	mu    sync.Mutex
+	nodes map[string]*nodeState
+	next  map[string]*uint64
+	// ports holds the ports other than the default one the nodes of each
+	// chain answered calls on, for their health to be checked too.
+	ports map[string]map[int]bool
+
+	stopHealth context.CancelFunc
+	healthWG   sync.WaitGroup
+}
+
+type nodeState struct {
+	mu           sync.Mutex
+	ejectedUntil time.Time
+	latency      time.Duration
+	health       NodeHealth
+	checked      bool
+
+	verifyMu     sync.Mutex
+	verifiedConn *grpc.ClientConn
+	verifiedAt   time.Time
+	verifyErr    error
+}
+
+// ejected reports whether the node should be kept out of rotation, either
+// because it recently failed a call or because it failed its health check.
+func (n *nodeState) ejected(now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return now.Before(n.ejectedUntil) || (n.checked && !n.health.Healthy)
+}
+
+func (n *nodeState) setHealth(h NodeHealth) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.health = h
+	n.checked = true
+}
+
+func (n *nodeState) lastHealth() (NodeHealth, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.health, n.checked
+}
+
+func (n *nodeState) eject(d time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.ejectedUntil = time.Now().Add(d)
+}
+
+func (n *nodeState) observe(latency time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.ejectedUntil = time.Time{}
+	if n.latency == 0 {
+		n.latency = latency
+		return
+	}
+
+	n.latency = time.Duration(latencyDecay*float64(latency) + (1-latencyDecay)*float64(n.latency))
+}
+
+func (n *nodeState) avgLatency() time.Duration {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.latency
+}
+
+// NewUpstream returns an Upstream which gets its connections from pool.
+func NewUpstream(pool *ConnPool) *Upstream {
+	return &Upstream{
+		pool:    pool,
+		flights: newFlightGroup(),
+		nodes:   map[string]*nodeState{},
+		next:    map[string]*uint64{},
+		ports:   map[string]map[int]bool{},
+	}
+}
+
+// Close stops health checks and closes the connections to all the nodes.
+func (u *Upstream) Close() error {
+	if u.stopHealth != nil {
+		u.stopHealth()
+		u.healthWG.Wait()
+	}
+
+	return u.pool.Close()
+}
+
+// Conn returns a connection to chain, whose calls are balanced between its
+// nodes. Nodes without an explicit port are reached on port.
+func (u *Upstream) Conn(chain Chain, port *int) grpc.ClientConnInterface {
+	c := &chainConn{
+		upstream: u,
+		chain:    chain,
+		port:     port,
+		next:     u.counter(chain.Name),
+	}
+
+	for i := range chain.GRPC {
+		target := chain.GRPCTarget(i, port)
+		c.targets = append(c.targets, target)
+		c.nodes = append(c.nodes, u.node(target))
+	}
+
+	return c
+}
+
+func (u *Upstream) node(target string) *nodeState {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	n, ok := u.nodes[target]
+	if !ok {
+		n = &nodeState{}
+		u.nodes[target] = n
+	}
+
+	return n
+}
+
+// addPort records that the nodes of chainName answered calls on port.
+func (u *Upstream) addPort(chainName string, port int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	ports, ok := u.ports[chainName]
+	if !ok {
+		ports = map[int]bool{}
+		u.ports[chainName] = ports
+	}
+
+	ports[port] = true
+}
+
+// targets returns the targets of the nodes of chain on the default port and
+// on the other ports they answered calls on.
+func (u *Upstream) targets(chain Chain) []string {
+	u.mu.Lock()
+	ports := make([]int, 0, len(u.ports[chain.Name]))
+	for p := range u.ports[chain.Name] {
+		ports = append(ports, p)
+	}
+	u.mu.Unlock()
+
+	sort.Ints(ports)
+
+	var ret []string
+	seen := map[string]bool{}
+	for i := range chain.GRPC {
+		targets := []string{chain.GRPCTarget(i, nil)}
+		for p := range ports {
+			targets = append(targets, chain.GRPCTarget(i, &ports[p]))
+		}
+
+		for _, t := range targets {
+			if !seen[t] {
+				seen[t] = true
+				ret = append(ret, t)
+			}
+		}
+	}
+
+	return ret
+}
+
+func (u *Upstream) counter(chainName string) *uint64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	c, ok := u.next[chainName]
+	if !ok {
+		c = new(uint64)
+		u.next[chainName] = c
+	}
+
+	return c
+}
+
+// chainConn implements grpc.ClientConnInterface on top of all the nodes of
+// a chain.
+type chainConn struct {
+	upstream *Upstream
+	chain    Chain
+	port     *int
+	targets  []string
+	nodes    []*nodeState
+	next     *uint64
+}
+
+// Invoke sends the call to one of the chain nodes. Concurrent identical
+// idempotent calls share a single upstream request.
+func (c *chainConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	// Call options may read the response headers or trailers, which aren't
+	// shared between coalesced calls.
+	if nonIdempotentMethods[method] || len(opts) > 0 {
+		return c.invoke(ctx, method, args, reply, opts...)
+	}
+
+	key, err := flightKey(ctx, c.chain.Name, method, args)
+	if err != nil {
+		return c.invoke(ctx, method, args, reply, opts...)
+	}
+
+	return c.upstream.flights.do(ctx, key, reply, func(ctx context.Context, reply interface{}) error {
+		return c.invoke(ctx, method, args, reply, opts...)
+	})
+}
+
+// invoke sends the call to one of the chain nodes. Idempotent calls failing
+// because the node is unavailable or too slow are retried on the next one.
+func (c *chainConn) invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	tried := make([]bool, len(c.nodes))
+	lastErr := errNoNodes
+
+	for range c.nodes {
+		idx := c.pick(tried)
+		if idx < 0 {
+			break
+		}
+
+		tried[idx] = true
+
+		conn, release, err := c.usableConn(ctx, idx)
+		if err != nil {
+			// Nothing was sent to the node, so trying another one is always safe.
+			lastErr = err
+			continue
+		}
+
+		start := time.Now()
+		err = conn.Invoke(ctx, method, args, reply, opts...)
+		release()
+		if err == nil || !isNodeFailure(err) {
+			c.nodes[idx].observe(time.Since(start))
+			if c.port != nil {
+				c.upstream.addPort(c.chain.Name, *c.port)
+			}
+
+			return err
+		}
+
+		lastErr = err
+		c.nodes[idx].eject(c.chain.ejectionTime())
+
+		if nonIdempotentMethods[method] || ctx.Err() != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("chain %s: %w", c.chain.Name, lastErr)
+}
+
+// NewStream opens a stream on one of the chain nodes, without retries. The
+// connection is kept in use until the stream ends or ctx is done.
+func (c *chainConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	tried := make([]bool, len(c.nodes))
+
+	for range c.nodes {
+		idx := c.pick(tried)
+		if idx < 0 {
+			break
+		}
+
+		tried[idx] = true
+
+		conn, release, err := c.usableConn(ctx, idx)
+		if err != nil {
+			continue
+		}
+
+		stream, err := conn.NewStream(ctx, desc, method, opts...)
+		if err != nil {
+			release()
+			return nil, err
+		}
+
+		return newReleasingStream(ctx, stream, release), nil
+	}
+
+	return nil, fmt.Errorf("chain %s: %w", c.chain.Name, errNoNodes)
+}
+
+// usableConn returns the connection to the idx-th node, unless it is known
+// to be failing or to serve another chain, and the function releasing it.
+func (c *chainConn) usableConn(ctx context.Context, idx int) (*grpc.ClientConn, func(), error) {
+	conn, release, err := c.upstream.pool.Get(c.targets[idx], c.chain.TransportKey(), c.chain.DialOptions()...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if conn.GetState() == connectivity.TransientFailure {
+		release()
+		c.nodes[idx].eject(c.chain.ejectionTime())
+		return nil, nil, fmt.Errorf("node %s: %w", c.targets[idx], errNoNodes)
+	}
+
+	if err := c.upstream.verify(ctx, c.chain, c.targets[idx], conn); err != nil {
+		release()
+		c.nodes[idx].eject(c.chain.ejectionTime())
+		return nil, nil, err
+	}
+
+	return conn, release, nil
+}
+
+// releasingStream releases its connection once the stream ends, when a
+// message can't be received anymore or its context is done.
+type releasingStream struct {
+	grpc.ClientStream
+
+	once    sync.Once
+	release func()
+	done    chan struct{}
+}
+
+func newReleasingStream(ctx context.Context, stream grpc.ClientStream, release func()) *releasingStream {
+	s := &releasingStream{
+		ClientStream: stream,
+		release:      release,
+		done:         make(chan struct{}),
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.end()
+		case <-s.done:
+		}
+	}()
+
+	return s
+}
+
+func (s *releasingStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.end()
+	}
+
+	return err
+}
+
+func (s *releasingStream) end() {
+	s.once.Do(func() {
+		close(s.done)
+		s.release()
+	})
+}
+
+// pick returns the index of the next node to call among the ones not tried
+// yet, or -1 if all of them have been tried.
+// Ejected nodes are only picked when no other node is left.
+func (c *chainConn) pick(tried []bool) int {
+	now := time.Now()
+
+	candidates := make([]int, 0, len(c.nodes))
+	for i, n := range c.nodes {
+		if !tried[i] && !n.ejected(now) {
+			candidates = append(candidates, i)
+		}
+	}
+
+	if len(candidates) == 0 {
+		for i := range c.nodes {
+			if !tried[i] {
+				candidates = append(candidates, i)
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return -1
+	}
+
+	if c.chain.Balancing == LeastLatency {
+		best := candidates[0]
+		for _, i := range candidates[1:] {
+			if c.nodes[i].avgLatency() < c.nodes[best].avgLatency() {
+				best = i
+			}
+		}
+
+		return best
+	}
+
+	next := atomic.AddUint64(c.next, 1)
+	return candidates[next%uint64(len(candidates))]
+}
+
+// isNodeFailure reports whether err means that the node couldn't serve the
+// call, as opposed to the call itself failing.
+func isNodeFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,301 @@
+package sdkservice
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/require"
+	tmp2p "github.com/tendermint/tendermint/proto/tendermint/p2p"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const nodeInfoMethod = "/cosmos.base.tendermint.v1beta1.Service/GetNodeInfo"
+
+// callLog records the nodes called, in order, by the balancedNodes sharing
+// it.
+type callLog struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (l *callLog) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.names = append(l.names, name)
+}
+
+// take returns the nodes called since the last take.
+func (l *callLog) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ret := l.names
+	l.names = nil
+
+	return ret
+}
+
+// balancedNode is a local gRPC server answering any method, either with
+// the error set or with node info giving its name as the network.
+type balancedNode struct {
+	name   string
+	target string
+	log    *callLog
+
+	mu  sync.Mutex
+	err error
+}
+
+func newBalancedNode(t *testing.T, name string, log *callLog) *balancedNode {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	n := &balancedNode{name: name, target: lis.Addr().String(), log: log}
+
+	s := grpc.NewServer(grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+		// Requests are all read as an empty message, keeping their fields
+		// as unknown ones.
+		if err := stream.RecvMsg(&tmservice.GetNodeInfoRequest{}); err != nil {
+			return err
+		}
+
+		n.log.add(n.name)
+
+		if err := n.failure(); err != nil {
+			return err
+		}
+
+		return stream.SendMsg(&tmservice.GetNodeInfoResponse{
+			DefaultNodeInfo: &tmp2p.DefaultNodeInfo{Network: n.name},
+		})
+	}))
+
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	return n
+}
+
+func (n *balancedNode) setErr(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.err = err
+}
+
+func (n *balancedNode) failure() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.err
+}
+
+// balancedChain returns a chain served by three nodes, named n0 to n2.
+func balancedChain(t *testing.T, balancing string, ejectionTime time.Duration) (Chain, []*balancedNode, *callLog) {
+	log := &callLog{}
+
+	var (
+		nodes   []*balancedNode
+		targets []string
+	)
+	for _, name := range []string{"n0", "n1", "n2"} {
+		n := newBalancedNode(t, name, log)
+		nodes = append(nodes, n)
+		targets = append(targets, n.target)
+	}
+
+	r, err := NewChainRegistry([]Chain{{
+		Name:         "test",
+		GRPC:         targets,
+		Balancing:    balancing,
+		EjectionTime: Duration(ejectionTime),
+	}})
+	require.NoError(t, err)
+
+	chain, err := r.Chain("test")
+	require.NoError(t, err)
+
+	return chain, nodes, log
+}
+
+func newTestUpstream(t *testing.T) *Upstream {
+	u := NewUpstream(NewConnPool(0))
+	t.Cleanup(func() { _ = u.Close() })
+
+	return u
+}
+
+// nodeInfo calls GetNodeInfo through conn, returning the name of the node
+// which answered.
+func nodeInfo(conn grpc.ClientConnInterface) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := tmservice.NewServiceClient(conn).GetNodeInfo(ctx, &tmservice.GetNodeInfoRequest{})
+	if err != nil {
+		return "", err
+	}
+
+	return res.DefaultNodeInfo.Network, nil
+}
+
+func TestUpstreamFailover(t *testing.T) {
+	for _, code := range []codes.Code{codes.Unavailable, codes.DeadlineExceeded} {
+		chain, nodes, log := balancedChain(t, RoundRobin, 0)
+		conn := newTestUpstream(t).Conn(chain, nil)
+
+		nodes[1].setErr(status.Error(code, "node failing"))
+
+		// The round robin starts on n1, which fails and is retried on the
+		// next node left.
+		name, err := nodeInfo(conn)
+		require.NoError(t, err, code)
+		require.Equal(t, "n0", name, code)
+		require.Equal(t, []string{"n1", "n0"}, log.take(), code)
+
+		// n1 is ejected, calls go round the others.
+		for i := 0; i < 4; i++ {
+			_, err := nodeInfo(conn)
+			require.NoError(t, err, code)
+		}
+		require.Equal(t, []string{"n2", "n0", "n2", "n0"}, log.take(), code)
+	}
+}
+
+func TestUpstreamCallFailureNotRetried(t *testing.T) {
+	// Calls failing for other reasons than the node don't eject it.
+	chain, nodes, log := balancedChain(t, RoundRobin, 0)
+	conn := newTestUpstream(t).Conn(chain, nil)
+
+	nodes[1].setErr(status.Error(codes.NotFound, "not found"))
+
+	_, err := nodeInfo(conn)
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, []string{"n1"}, log.take())
+
+	nodes[1].setErr(nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := nodeInfo(conn)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"n2", "n0", "n1"}, log.take())
+}
+
+func TestUpstreamBroadcastNotRetried(t *testing.T) {
+	chain, nodes, log := balancedChain(t, RoundRobin, 0)
+	conn := newTestUpstream(t).Conn(chain, nil)
+
+	for _, n := range nodes {
+		n.setErr(status.Error(codes.Unavailable, "connection reset"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The transaction may have been received by the node, it must not be
+	// sent to another one.
+	_, err := sdktx.NewServiceClient(conn).BroadcastTx(ctx, &sdktx.BroadcastTxRequest{TxBytes: []byte("tx")})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, []string{"n1"}, log.take())
+	require.True(t, nonIdempotentMethods[broadcastTxMethod])
+}
+
+func TestUpstreamEjectionExpiry(t *testing.T) {
+	chain, nodes, log := balancedChain(t, RoundRobin, 100*time.Millisecond)
+	conn := newTestUpstream(t).Conn(chain, nil)
+
+	nodes[1].setErr(status.Error(codes.Unavailable, "node restarting"))
+
+	_, err := nodeInfo(conn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"n1", "n0"}, log.take())
+
+	nodes[1].setErr(nil)
+
+	for i := 0; i < 4; i++ {
+		_, err := nodeInfo(conn)
+		require.NoError(t, err)
+	}
+	require.NotContains(t, log.take(), "n1")
+
+	// Once the ejection expires, the node is back in rotation.
+	time.Sleep(150 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		_, err := nodeInfo(conn)
+		require.NoError(t, err)
+	}
+	require.Contains(t, log.take(), "n1")
+}
+
+func TestUpstreamLeastLatency(t *testing.T) {
+	chain, nodes, log := balancedChain(t, LeastLatency, 0)
+	u := newTestUpstream(t)
+	conn := u.Conn(chain, nil)
+
+	for i, latency := range []time.Duration{time.Second, time.Microsecond, 500 * time.Millisecond} {
+		u.node(nodes[i].target).observe(latency)
+	}
+
+	for i := 0; i < 3; i++ {
+		name, err := nodeInfo(conn)
+		require.NoError(t, err)
+		require.Equal(t, "n1", name)
+	}
+	require.Equal(t, []string{"n1", "n1", "n1"}, log.take())
+
+	// The fastest node failing, calls go to the next fastest.
+	nodes[1].setErr(status.Error(codes.Unavailable, "node restarting"))
+
+	name, err := nodeInfo(conn)
+	require.NoError(t, err)
+	require.Equal(t, "n2", name)
+	require.Equal(t, []string{"n1", "n2"}, log.take())
+
+	_, err = nodeInfo(conn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"n2"}, log.take())
+}
+
+func TestUpstreamAllNodesEjected(t *testing.T) {
+	chain, nodes, log := balancedChain(t, RoundRobin, 0)
+	conn := newTestUpstream(t).Conn(chain, nil)
+
+	for _, n := range nodes {
+		n.setErr(status.Error(codes.Unavailable, "node restarting"))
+	}
+
+	_, err := nodeInfo(conn)
+	require.Equal(t, KindUpstreamUnavailable, ClassifyError(err).Kind)
+	require.ElementsMatch(t, []string{"n0", "n1", "n2"}, log.take())
+
+	// Ejected nodes are still tried when no other node is left.
+	nodes[2].setErr(nil)
+
+	name, err := nodeInfo(conn)
+	require.NoError(t, err)
+	require.Equal(t, "n2", name)
+}
+
+func TestUpstreamNoNodes(t *testing.T) {
+	conn := newTestUpstream(t).Conn(Chain{Name: "test"}, nil)
+
+	_, err := nodeInfo(conn)
+	require.True(t, errors.Is(err, errNoNodes))
+	require.Equal(t, KindUpstreamUnavailable, ClassifyError(err).Kind)
+
+	_, err = conn.NewStream(context.Background(), &grpc.StreamDesc{}, nodeInfoMethod)
+	require.True(t, errors.Is(err, errNoNodes))
+}
@@ -0,0 +1,95 @@
+package sdkservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"google.golang.org/grpc"
+)
+
+// ErrChainMismatch is returned when a node serves a different network than
+// the one configured for its chain.
+var ErrChainMismatch = errors.New("node serves a different chain")
+
+const verifyTimeout = healthCheckTimeout
+
+// verifyTTL is how long the outcome of a verification holds for a
+// connection. The connection of a node is kept across reconnections, which
+// may reach another node behind the same address.
+const verifyTTL = DefaultHealthCheckInterval
+
+// verify checks that the node behind conn serves the network configured for
+// chain. The outcome is kept for verifyTTL.
+// Chains with neither chain_id nor app_version set are not verified.
+func (u *Upstream) verify(ctx context.Context, chain Chain, target string, conn *grpc.ClientConn) error {
+	return u.verifyNode(ctx, chain, target, conn, false)
+}
+
+// reverify checks that the node behind conn serves the network configured for
+// chain, even if it was verified recently.
+func (u *Upstream) reverify(ctx context.Context, chain Chain, target string, conn *grpc.ClientConn) error {
+	return u.verifyNode(ctx, chain, target, conn, true)
+}
+
+func (u *Upstream) verifyNode(ctx context.Context, chain Chain, target string, conn *grpc.ClientConn, force bool) error {
+	if chain.ChainID == "" && chain.AppVersion == "" {
+		return nil
+	}
+
+	n := u.node(target)
+
+	n.verifyMu.Lock()
+	defer n.verifyMu.Unlock()
+
+	if !force && n.verifiedConn == conn && time.Since(n.verifiedAt) < verifyTTL {
+		return n.verifyErr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+
+	info, err := tmservice.NewServiceClient(conn).GetNodeInfo(ctx, &tmservice.GetNodeInfoRequest{})
+	if err != nil {
+		// Don't cache transient failures, try again on the next call.
+		return fmt.Errorf("node %s: cannot get node info, %w", target, err)
+	}
+
+	n.verifiedConn = conn
+	n.verifiedAt = time.Now()
+	n.verifyErr = checkNodeInfo(chain, info)
+	if n.verifyErr != nil {
+		n.verifyErr = fmt.Errorf("node %s: %w", target, n.verifyErr)
+	}
+
+	return n.verifyErr
+}
+
+func checkNodeInfo(chain Chain, info *tmservice.GetNodeInfoResponse) error {
+	if chain.ChainID != "" {
+		var network string
+		if info.DefaultNodeInfo != nil {
+			network = info.DefaultNodeInfo.Network
+		}
+
+		if network != chain.ChainID {
+			return fmt.Errorf("%w: expected chain id %s, got %q", ErrChainMismatch, chain.ChainID, network)
+		}
+	}
+
+	if chain.AppVersion != "" {
+		var version string
+		if info.ApplicationVersion != nil {
+			version = info.ApplicationVersion.Version
+		}
+
+		if !strings.HasPrefix(version, chain.AppVersion) {
+			return fmt.Errorf("%w: expected app version %s, got %q", ErrChainMismatch, chain.AppVersion, version)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package sdkservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestVerifyNodeReplaced(t *testing.T) {
+	node := newTestNode(t)
+	node.setNetwork("cosmoshub-4")
+
+	u := NewUpstream(NewConnPool(0))
+	defer u.Close()
+
+	chain := testChain(t, node.target, TLSConfig{}, AuthConfig{})
+	chain.ChainID = "cosmoshub-4"
+
+	conn, release, err := u.pool.Get(node.target, chain.TransportKey(), chain.DialOptions()...)
+	require.NoError(t, err)
+	defer release()
+
+	ctx := context.Background()
+	require.NoError(t, u.verify(ctx, chain, node.target, conn))
+
+	// Another node now answers behind the same address, through the same
+	// connection.
+	node.setNetwork("theta-testnet-001")
+
+	u.CheckHealth(ctx, []Chain{chain})
+
+	health := u.Health(chain)
+	require.False(t, health.Nodes[0].Healthy)
+	require.Contains(t, health.Nodes[0].Reason, "expected chain id cosmoshub-4")
+
+	err = u.verify(ctx, chain, node.target, conn)
+	require.True(t, errors.Is(err, ErrChainMismatch))
+
+	_, err = tmservice.NewServiceClient(u.Conn(chain, nil)).GetNodeInfo(ctx, &tmservice.GetNodeInfoRequest{})
+	require.True(t, errors.Is(err, ErrChainMismatch))
+
+	// The right node coming back is verified again on the next check.
+	node.setNetwork("cosmoshub-4")
+
+	u.CheckHealth(ctx, []Chain{chain})
+	require.True(t, u.Health(chain).Nodes[0].Healthy)
+	require.NoError(t, u.verify(ctx, chain, node.target, conn))
+}
+
+func TestClassifyChainMismatch(t *testing.T) {
+	e := ClassifyError(errors.New("unrelated"))
+	require.Equal(t, KindInternal, e.Kind)
+
+	e = ClassifyError(queryError("bank", fmt.Errorf("chain test: node 127.0.0.1:9090: %w", ErrChainMismatch)))
+	require.Equal(t, KindChainMismatch, e.Kind)
+	require.Equal(t, http.StatusMisdirectedRequest, e.StatusCode())
+	require.Equal(t, codes.FailedPrecondition, status.Convert(e).Code())
+}